@@ -2,175 +2,274 @@ package main
 
 import (
 	"context"
-	"embed"
 	"fmt"
 	"os"
-	"runtime/debug"
+	"path/filepath"
+	"sync"
 
 	"github.com/oklog/ulid/v2"
 	"github.com/samber/lo"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 
+	"ra2-ini-editor/internal/fileutil"
+	"ra2-ini-editor/internal/history"
+	"ra2-ini-editor/internal/project"
 	"ra2-ini-editor/internal/ra2"
+	"ra2-ini-editor/internal/recent"
+	"ra2-ini-editor/internal/recovery"
+	"ra2-ini-editor/internal/workspace"
 )
 
-//go:embed data
-var dataFs embed.FS
-
 // App struct
 type App struct {
 	ctx context.Context
 
+	// mu 保护以下所有状态。Wails 会并发调用绑定的方法，
+	// 导出方法在入口处加锁，打开文件对话框时不持有锁。
+	mu sync.RWMutex
+
 	schema      *ra2.Schema
-	origin      *ra2.Rules
 	translation *ra2.Translation
 
-	rules *ra2.Rules
+	project     *project.Project
+	projectPath string // 为空表示未保存的默认项目
+	gameFiles   *ra2.GameFiles
+
+	layers  *ra2.LayerStack
+	ai      *ra2.Layer
+	sound   *ra2.Layer
+	eva     *ra2.Layer
+	history *history.History
+
+	recentFiles       *recent.Files
+	recovery          *recovery.Store
+	revision          uint64 // 每次修改后递增
+	autosavedRevision uint64
 }
 
 // NewApp creates a new App application struct
-func NewApp() *App {
-	schemaFile, err := dataFs.Open("data/schema/schema.zh.json")
-	if err != nil {
-		panic(err)
-	}
-	defer schemaFile.Close()
-	schema, err := ra2.LoadSchema(schemaFile)
-	if err != nil {
-		panic(err)
-	}
-
-	originFile, err := dataFs.Open("data/rulesmd.ini")
+func NewApp() (*App, error) {
+	configDir, err := getConfigDir()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	defer originFile.Close()
-	origin, err := ra2.NewRules(originFile)
+	recentFiles, err := recent.Load(filepath.Join(configDir, "recent.json"))
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
+	a := &App{
+		history: history.New(MaxHistorySize),
 
-	translationFile, err := dataFs.Open("data/ra2md.ini")
-	if err != nil {
-		panic(err)
+		recentFiles: recentFiles,
+		recovery:    recovery.NewStore(filepath.Join(configDir, "recovery")),
 	}
-	defer translationFile.Close()
-	translation, err := ra2.LoadTranslation(translationFile, "zh-TW")
-	if err != nil {
-		panic(err)
-	}
-	return &App{
-		schema:      schema,
-		origin:      origin,
-		translation: translation,
-
-		rules: ra2.NewEmptyRules(),
+	if err := a.loadProject(project.Default()); err != nil {
+		return nil, err
 	}
+	return a, nil
 }
 
 // startup is called when the app starts. The context is saved
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	go a.autosaveLoop(ctx)
 }
 
-type AppError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-}
-
-func NewAppError(code int, msg string) *AppError {
-	return &AppError{
-		Code:    code,
-		Message: msg,
-	}
-}
-
-func NewAppErrorf(code int, format string, args ...interface{}) *AppError {
-	return &AppError{
-		Code:    code,
-		Message: fmt.Sprintf(format, args...),
+// shutdown is called when the app terminates. Unsaved changes are
+// written to the recovery directory so they can be restored next time.
+func (a *App) shutdown(ctx context.Context) {
+	if err := a.autosave(); err != nil {
+		runtime.LogError(ctx, fmt.Sprintf("autosave error: %v", err))
 	}
 }
 
-func (e *AppError) Error() string {
-	return fmt.Sprintf("code=%d, message=%s", e.Code, e.Message)
-}
-
 func (a *App) NewULID() string {
 	return ulid.Make().String()
 }
 
-func (a *App) Open() error {
+func (a *App) Open() (err error) {
+	defer a.recoverPanic(&err)
 	filename, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
-		Title: "选择一个文件",
-		Filters: []runtime.FileFilter{
-			{Pattern: "*.ini", DisplayName: "INI Files (*.ini)"},
-		},
+		Title:   "选择一个文件",
+		Filters: layerFileFilters,
 	})
 	if err != nil {
-		return NewAppErrorf(500, "open file dialog error: %v", err)
+		return NewAppErrorf(ErrorCodeIO, "open file dialog error: %v", err)
 	}
 	if filename == "" {
-		return NewAppError(400, "no file selected")
+		return NewAppError(ErrorCodeValidation, "no file selected")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.openFile(filename)
+}
+
+// OpenRecent 打开最近文件列表中的 filename。
+func (a *App) OpenRecent(filename string) (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		if err := a.recentFiles.Remove(filename); err != nil {
+			runtime.LogError(a.ctx, fmt.Sprintf("remove recent file error: %v", err))
+		}
+		return NewAppErrorf(ErrorCodeNotFound, "file %s not found", filename)
+	}
+	return a.openFile(filename)
+}
+
+// openFile 将 filename 的内容载入写入目标层，并关联该文件路径。
+func (a *App) openFile(filename string) error {
+	target := a.layers.Target()
+	if target == nil {
+		return NewAppError(ErrorCodeValidation, "no write target layer")
 	}
 
-	rulesFile, err := os.Open(filename)
+	loaded, err := workspace.LoadLayer(filename)
+	if err != nil {
+		return newLoadError(err, "load rules error")
+	}
+	snap, err := loaded.Rules.Snapshot()
 	if err != nil {
-		return NewAppErrorf(500, "open file error: %v", err)
+		return NewAppErrorf(ErrorCodeInternal, "snapshot rules error: %v", err)
 	}
-	defer rulesFile.Close()
-	rules, err := ra2.NewRules(rulesFile)
+	err = a.transactFile("open "+filepath.Base(filename), target, snap, layerFile{
+		name: filepath.Base(filename),
+		path: filename,
+		m:    loaded.Map,
+	})
 	if err != nil {
-		return NewAppErrorf(500, "load rules error: %v", err)
+		return err
 	}
-	a.rules = rules
+	a.addRecentFile(filename)
 	return nil
 }
 
-func (a *App) Save() error {
-	if a.rules == nil {
-		return NewAppError(400, "no file opened")
+// Save 将写入目标层保存到其关联的文件，没有关联文件时等同于 SaveAs。
+func (a *App) Save() (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	target := a.layers.Target()
+	if target != nil && target.Path != "" {
+		defer a.mu.Unlock()
+		return a.saveLayer(target, target.Path)
+	}
+	a.mu.Unlock()
+
+	if target == nil {
+		return NewAppError(ErrorCodeValidation, "no write target layer")
+	}
+	return a.SaveAs()
+}
+
+// SaveAs 选择一个文件保存写入目标层，并将该层关联到新文件。
+func (a *App) SaveAs() (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	target := a.layers.Target()
+	if target == nil {
+		a.mu.RUnlock()
+		return NewAppError(ErrorCodeValidation, "no write target layer")
 	}
+	filters := layerFileFilters[:1]
+	if target.Map != nil {
+		// 地图文件层只能保存为地图
+		filters = layerFileFilters[1:]
+	}
+	defaultFilename := target.Name
+	a.mu.RUnlock()
 
 	filename, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
-		Title: "保存文件",
-		Filters: []runtime.FileFilter{
-			{Pattern: "*.ini", DisplayName: "INI Files (*.ini)"},
-		},
+		Title:           "保存文件",
+		DefaultFilename: defaultFilename,
+		Filters:         filters,
 	})
 	if err != nil {
-		return NewAppErrorf(500, "save file dialog error: %v", err)
+		return NewAppErrorf(ErrorCodeIO, "save file dialog error: %v", err)
 	}
-
 	if filename == "" {
-		return NewAppError(400, "no file selected")
+		return NewAppError(ErrorCodeValidation, "no file selected")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.hasLayer(target) {
+		return NewAppErrorf(ErrorCodeConflict, "layer %s has been removed", target.Name)
+	}
+	if err := a.saveLayer(target, filename); err != nil {
+		return err
 	}
-	rulesFile, err := os.Create(filename)
+	target.Name = filepath.Base(filename)
+	return nil
+}
+
+func (a *App) saveLayer(layer *ra2.Layer, filename string) error {
+	content, err := layer.Content()
 	if err != nil {
-		return NewAppErrorf(500, "create file error: %v", err)
+		return NewAppErrorf(ErrorCodeInternal, "get rules content error: %v", err)
 	}
-	defer rulesFile.Close()
-	if err := a.rules.Save(rulesFile); err != nil {
-		return NewAppErrorf(500, "save rules error: %v", err)
+	if err := fileutil.WriteFileAtomic(filename, content, true); err != nil {
+		return NewAppErrorf(ErrorCodeIO, "save rules error: %v", err)
 	}
+	layer.Path = filename
+	layer.Dirty = false
+	a.revision++
+	a.addRecentFile(filename)
 	return nil
 }
 
-func (a *App) UserRules() (string, error) {
-	bts, err := a.rules.Content()
+// ListRecentFiles 返回最近打开或保存的文件，最近使用的排在最前。
+func (a *App) ListRecentFiles() (_ []string, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.recentFiles.List(), nil
+}
+
+func (a *App) addRecentFile(filename string) {
+	if err := a.recentFiles.Add(filename); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("add recent file error: %v", err))
+	}
+}
+
+func (a *App) UserRules() (_ string, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	target := a.layers.Target()
+	if target == nil {
+		return "", NewAppError(ErrorCodeValidation, "no write target layer")
+	}
+	bts, err := target.Rules.Content()
 	if err != nil {
-		return "", NewAppErrorf(500, "get rules content error: %v", err)
+		return "", NewAppErrorf(ErrorCodeInternal, "get rules content error: %v", err)
 	}
 	return string(bts), nil
 }
 
-func (a *App) getRules() *ra2.Rules {
-	r := a.origin
-	if a.rules != nil {
-		r = lo.Must(r.Merge(a.rules))
+// getRules 返回合并后的规则，结果与规则栈共享，不能修改。
+func (a *App) getRules() (*ra2.Rules, error) {
+	r, err := a.layers.Merged()
+	if err != nil {
+		return nil, NewAppErrorf(ErrorCodeInternal, "merge layers error: %v", err)
+	}
+	return r, nil
+}
+
+// targetRules 返回写入目标层及其之下合并后的规则。修改只写入目标层，按此计划修改时
+// 不会把被上层覆盖的 key 当作修改成功。
+func (a *App) targetRules() (*ra2.Layer, *ra2.Rules, error) {
+	target := a.layers.Target()
+	if target == nil {
+		return nil, nil, NewAppError(ErrorCodeValidation, "no write target layer")
 	}
-	return r
+	r, err := a.layers.Through(target.ID)
+	if err != nil {
+		return nil, nil, NewAppErrorf(ErrorCodeInternal, "merge layers error: %v", err)
+	}
+	return target, r, nil
 }
 
 type Property struct {
@@ -180,6 +279,17 @@ type Property struct {
 	Comment string `json:"comment"`
 
 	Desc *string `json:"desc"`
+	// schema 中没有该 key 时拼写相近的 key，供编辑器提示更正
+	Suggestions []string `json:"suggestions,omitempty"`
+
+	// 对下层已有 key 的处理方式，见 ra2.KeyAction
+	Action string `json:"action"`
+	// 写入目标层之下的值，为空表示下层没有该 key
+	Origin *string `json:"origin"`
+
+	// 提供生效值的层
+	SourceID string `json:"source_id"`
+	Source   string `json:"source"`
 }
 
 type Unit struct {
@@ -190,15 +300,14 @@ type Unit struct {
 	Properties []Property `json:"properties"`
 }
 
-func (a *App) ListAllUnits() ([]*Unit, error) {
-	defer func() {
-		if err := recover(); err != nil {
-			runtime.LogError(a.ctx, fmt.Sprintf("panic: %v", err))
-			runtime.LogError(a.ctx, string(debug.Stack()))
-		}
-	}()
-
-	r := a.getRules()
+func (a *App) ListAllUnits() (_ []*Unit, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	r, err := a.getRules()
+	if err != nil {
+		return nil, err
+	}
 
 	units := make([]*Unit, 0)
 	for _, unit := range r.Units() {
@@ -213,12 +322,28 @@ func (a *App) ListAllUnits() ([]*Unit, error) {
 	return units, nil
 }
 
-func (a *App) GetUnit(unitType string, id int) (*Unit, error) {
-	r := a.getRules()
+func (a *App) GetUnit(unitType string, id int) (_ *Unit, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	r, err := a.getRules()
+	if err != nil {
+		return nil, err
+	}
 
 	unit := r.GetUnit(ra2.NewUnitType(unitType), id)
 	if unit == nil {
-		return nil, NewAppErrorf(404, "unit not found")
+		return nil, NewAppErrorf(ErrorCodeNotFound, "unit not found")
+	}
+
+	var originUnit, userUnit *ra2.Unit
+	if target := a.layers.Target(); target != nil {
+		origin, err := a.layers.Below(target.ID)
+		if err != nil {
+			return nil, NewAppErrorf(ErrorCodeInternal, "merge layers error: %v", err)
+		}
+		originUnit = origin.GetUnit(unit.Type, unit.ID)
+		userUnit = target.Rules.GetUnit(unit.Type, unit.ID)
 	}
 
 	availableProps := a.schema.ListAvailableUnitProperties(unit.Type)
@@ -235,6 +360,18 @@ func (a *App) GetUnit(unitType string, id int) (*Unit, error) {
 		})
 		if ok {
 			prop.Desc = lo.ToPtr(schemaProp.Desc.Get("zh"))
+		} else {
+			prop.Suggestions = a.schema.Suggest(unit.Type, unit.Name, prop.Key)
+		}
+		if originUnit != nil && originUnit.Has(prop.Key) {
+			prop.Origin = lo.ToPtr(originUnit.Get(prop.Key))
+			if userUnit != nil && userUnit.Has(prop.Key) && prop.Value == "" && *prop.Origin != "" {
+				prop.Action = string(ra2.KeyActionClear)
+			}
+		}
+		if source := a.layers.Source(unit.Name, prop.Key); source != nil {
+			prop.SourceID = source.ID
+			prop.Source = source.Name
 		}
 		props = append(props, prop)
 	}
@@ -248,7 +385,10 @@ func (a *App) GetUnit(unitType string, id int) (*Unit, error) {
 	}, nil
 }
 
-func (a *App) ListAvailableProperties(unitType string) ([]Property, error) {
+func (a *App) ListAvailableProperties(unitType string) (_ []Property, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	availableProps := a.schema.ListAvailableUnitProperties(ra2.NewUnitType(unitType))
 	props := make([]Property, 0)
 	for _, prop := range availableProps {
@@ -263,8 +403,27 @@ func (a *App) ListAvailableProperties(unitType string) ([]Property, error) {
 	return props, nil
 }
 
-func (a *App) NextUnitID(unitType string) (int, error) {
-	r := a.getRules()
+// SuggestUnitKeys 返回与 key 拼写相近的 unitType 可用属性，key 为已知属性时返回空列表。
+// 编辑器在输入新属性时用它提示更正。
+func (a *App) SuggestUnitKeys(unitType string, key string) (_ []string, err error) {
+	defer a.recoverPanic(&err)
+	ut := ra2.NewUnitType(unitType)
+	if ut == ra2.UnitTypeUnknown {
+		return nil, NewAppErrorf(ErrorCodeValidation, "unknown unit type %q", unitType)
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return nonNil(a.schema.Suggest(ut, "", key)), nil
+}
+
+func (a *App) NextUnitID(unitType string) (_ int, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	r, err := a.getRules()
+	if err != nil {
+		return 0, err
+	}
 
 	maxID := 0
 	for _, unit := range r.UnitsByType(ra2.NewUnitType(unitType)) {
@@ -276,31 +435,74 @@ func (a *App) NextUnitID(unitType string) (int, error) {
 	return maxID + 1, nil
 }
 
-func (a *App) SaveUnit(mod *Unit) error {
+// SaveUnit 将 mod 相对下层的差异写入目标层。每个属性按 Action 处理，
+// 下层已有但 mod 中没有出现的属性视为 KeyActionInherit。
+func (a *App) SaveUnit(mod *Unit) (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	target := a.layers.Target()
+	if target == nil {
+		return NewAppError(ErrorCodeValidation, "no write target layer")
+	}
+	origin, err := a.layers.Below(target.ID)
+	if err != nil {
+		return NewAppErrorf(ErrorCodeInternal, "merge layers error: %v", err)
+	}
+
+	unitType := ra2.NewUnitType(mod.Type)
 	modProps := make([]ra2.Property, 0)
+	modActions := make(map[string]ra2.KeyAction)
 	for _, prop := range mod.Properties {
+		action := ra2.NewKeyAction(prop.Action)
+		value := prop.Value
+		switch action {
+		case ra2.KeyActionClear:
+			value = ""
+		case ra2.KeyActionReset:
+			defaultValue, ok := a.schema.DefaultValue(unitType, prop.Key)
+			if !ok {
+				return NewAppErrorf(ErrorCodeValidation, "no default value for %s", prop.Key)
+			}
+			value = defaultValue
+		}
+		modActions[prop.Key] = action
 		modProps = append(modProps, ra2.Property{
 			Key:     prop.Key,
-			Value:   prop.Value,
+			Value:   value,
 			Comment: prop.Comment,
 		})
 	}
 
-	originUnit := a.origin.GetUnit(ra2.NewUnitType(mod.Type), mod.ID)
+	sections := []string{string(unitType.Section()), mod.Name}
+	return a.transact("save unit "+mod.Name, target, sections, func() error {
+		return a.saveUnit(target, origin.GetUnit(unitType, mod.ID), mod, modProps, modActions)
+	})
+}
+
+func (a *App) saveUnit(target *ra2.Layer, originUnit *ra2.Unit, mod *Unit, modProps []ra2.Property, modActions map[string]ra2.KeyAction) error {
+	unitType := ra2.NewUnitType(mod.Type)
 	if originUnit == nil {
-		_, err := a.rules.AddUnit(ra2.NewUnitType(mod.Type), mod.ID, mod.Name, modProps)
+		modProps = lo.Filter(modProps, func(p ra2.Property, _ int) bool {
+			return modActions[p.Key] != ra2.KeyActionInherit
+		})
+		_, err := target.Rules.AddUnit(unitType, mod.ID, mod.Name, modProps)
 		if err != nil {
-			return NewAppErrorf(500, "add unit error: %v", err)
+			return NewAppErrorf(ErrorCodeInternal, "add unit error: %v", err)
 		}
 		return nil
 	}
 
-	userUnit := a.rules.GetUnit(ra2.NewUnitType(mod.Type), mod.ID)
+	userUnit := target.Rules.GetUnit(unitType, mod.ID)
+	if userUnit == nil {
+		// 目标层中没有注册但已有 section，如地图中的单位覆盖
+		userUnit = target.Rules.UnitSection(unitType, mod.ID, mod.Name)
+	}
 	if userUnit == nil {
 		// 新建用户级 unit
-		unit, err := a.rules.AddUnit(ra2.NewUnitType(mod.Type), mod.ID, mod.Name, nil)
+		unit, err := target.Rules.AddUnit(unitType, mod.ID, mod.Name, nil)
 		if err != nil {
-			return NewAppErrorf(500, "add unit error: %v", err)
+			return NewAppErrorf(ErrorCodeInternal, "add unit error: %v", err)
 		}
 		userUnit = unit
 	}
@@ -308,15 +510,21 @@ func (a *App) SaveUnit(mod *Unit) error {
 	originProps := originUnit.Properties()
 	userProps := userUnit.Properties()
 	for _, modProp := range modProps {
-		prop, ok := lo.Find(originProps, func(p ra2.Property) bool {
-			return p.Key == modProp.Key
-		})
-		if !ok {
-			userUnit.Set(modProp.Key, modProp.Value, modProp.Comment)
-		} else {
-			if prop.Value != modProp.Value || prop.Comment != modProp.Comment {
+		switch modActions[modProp.Key] {
+		case ra2.KeyActionInherit:
+			userUnit.Del(modProp.Key)
+		case ra2.KeyActionSet:
+			prop, ok := lo.Find(originProps, func(p ra2.Property) bool {
+				return p.Key == modProp.Key
+			})
+			if ok && prop.Value == modProp.Value && prop.Comment == modProp.Comment {
+				// 与下层一致，无需覆盖
+				userUnit.Del(modProp.Key)
+			} else {
 				userUnit.Set(modProp.Key, modProp.Value, modProp.Comment)
 			}
+		default:
+			userUnit.Set(modProp.Key, modProp.Value, modProp.Comment)
 		}
 	}
 	for _, userProp := range userProps {
@@ -327,34 +535,39 @@ func (a *App) SaveUnit(mod *Unit) error {
 			userUnit.Del(userProp.Key)
 		}
 	}
-	for _, originProp := range originProps {
-		_, ok := lo.Find(modProps, func(p ra2.Property) bool {
-			return p.Key == originProp.Key
-		})
-		if !ok {
-			userUnit.Set(originProp.Key, "")
-		}
-	}
 
 	return nil
 }
 
-func (a *App) DeleteUnit(unitType string, id int) error {
-	r := a.getRules()
+func (a *App) DeleteUnit(unitType string, id int) (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	target := a.layers.Target()
+	if target == nil {
+		return NewAppError(ErrorCodeValidation, "no write target layer")
+	}
+
+	r, err := a.getRules()
+	if err != nil {
+		return err
+	}
 
 	unit := r.GetUnit(ra2.NewUnitType(unitType), id)
 	if unit == nil {
-		return NewAppErrorf(404, "unit not found")
+		return NewAppErrorf(ErrorCodeNotFound, "unit not found")
 	}
 
-	userUnit := a.rules.GetUnit(ra2.NewUnitType(unitType), id)
+	userUnit := target.Rules.GetUnit(ra2.NewUnitType(unitType), id)
 	if userUnit == nil {
-		return NewAppErrorf(400, "cannot delete unit from origin rules")
+		return NewAppErrorf(ErrorCodeValidation, "cannot delete unit from underlying layers")
 	}
 
-	if err := a.rules.DelUnit(userUnit.Type, userUnit.ID); err != nil {
-		return NewAppErrorf(500, "delete unit error: %v", err)
-	}
-
-	return nil
+	sections := []string{string(userUnit.Type.Section()), userUnit.Name}
+	return a.transact("delete unit "+userUnit.Name, target, sections, func() error {
+		if err := target.Rules.DelUnit(userUnit.Type, userUnit.ID); err != nil {
+			return NewAppErrorf(ErrorCodeInternal, "delete unit error: %v", err)
+		}
+		return nil
+	})
 }
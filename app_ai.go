@@ -0,0 +1,247 @@
+package main
+
+import (
+	"strings"
+
+	"ra2-ini-editor/internal/ra2"
+)
+
+func (a *App) GetAIFile() (_ *Document, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return newDocument(a.ai), nil
+}
+
+// OpenAI 选择一个 aimd.ini 替换当前的 AI 文件。
+func (a *App) OpenAI() (err error) {
+	defer a.recoverPanic(&err)
+	return a.openDocument("打开 AI 文件", &a.ai)
+}
+
+// SaveAI 将 AI 文件保存到其关联的文件，没有关联文件时选择一个文件。
+func (a *App) SaveAI() (err error) {
+	defer a.recoverPanic(&err)
+	return a.saveDocument(&a.ai, "保存 AI 文件")
+}
+
+func (a *App) aiView() *ra2.AI {
+	return ra2.NewAI(a.ai.Rules)
+}
+
+func (a *App) ListTaskForces() (_ []*ra2.TaskForce, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	taskForces, err := a.aiView().TaskForces()
+	if err != nil {
+		return nil, NewAppErrorf(ErrorCodeParse, "list task forces error: %v", err)
+	}
+	return nonNil(taskForces), nil
+}
+
+// SaveTaskForce 新建或更新 TaskForce，ID 为空时分配新 ID。
+func (a *App) SaveTaskForce(tf *ra2.TaskForce) (_ *ra2.TaskForce, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ai := a.aiView()
+	if tf.ID == "" {
+		tf.ID = ai.NewID()
+	}
+	err = a.transact("save task force "+tf.ID, a.ai, []string{string(ra2.SectionNameTaskForces), tf.ID}, func() error {
+		if err := ai.SetTaskForce(tf); err != nil {
+			return NewAppErrorf(ErrorCodeValidation, "save task force error: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tf, nil
+}
+
+func (a *App) DeleteTaskForce(id string) (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.deleteAIObject("task force", id, ra2.SectionNameTaskForces, a.aiView().DelTaskForce)
+}
+
+func (a *App) ListScriptTypes() (_ []*ra2.ScriptType, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	scripts, err := a.aiView().ScriptTypes()
+	if err != nil {
+		return nil, NewAppErrorf(ErrorCodeParse, "list script types error: %v", err)
+	}
+	return nonNil(scripts), nil
+}
+
+// SaveScriptType 新建或更新 ScriptType，ID 为空时分配新 ID。
+func (a *App) SaveScriptType(st *ra2.ScriptType) (_ *ra2.ScriptType, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ai := a.aiView()
+	if st.ID == "" {
+		st.ID = ai.NewID()
+	}
+	err = a.transact("save script type "+st.ID, a.ai, []string{string(ra2.SectionNameScriptTypes), st.ID}, func() error {
+		if err := ai.SetScriptType(st); err != nil {
+			return NewAppErrorf(ErrorCodeValidation, "save script type error: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+func (a *App) DeleteScriptType(id string) (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.deleteAIObject("script type", id, ra2.SectionNameScriptTypes, a.aiView().DelScriptType)
+}
+
+func (a *App) ListTeamTypes() (_ []*ra2.TeamType, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	teams, err := a.aiView().TeamTypes()
+	if err != nil {
+		return nil, NewAppErrorf(ErrorCodeParse, "list team types error: %v", err)
+	}
+	return nonNil(teams), nil
+}
+
+// SaveTeamType 新建或更新 TeamType，ID 为空时分配新 ID。
+// 引用的 TaskForce 与 ScriptType 必须存在。
+func (a *App) SaveTeamType(tt *ra2.TeamType) (_ *ra2.TeamType, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ai := a.aiView()
+	if tf, _ := ai.TaskForce(tt.TaskForce); tf == nil {
+		return nil, NewAppErrorf(ErrorCodeValidation, "task force %s not found", tt.TaskForce)
+	}
+	if st, _ := ai.ScriptType(tt.Script); st == nil {
+		return nil, NewAppErrorf(ErrorCodeValidation, "script type %s not found", tt.Script)
+	}
+	if tt.ID == "" {
+		tt.ID = ai.NewID()
+	}
+	err = a.transact("save team type "+tt.ID, a.ai, []string{string(ra2.SectionNameTeamTypes), tt.ID}, func() error {
+		if err := ai.SetTeamType(tt); err != nil {
+			return NewAppErrorf(ErrorCodeValidation, "save team type error: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tt, nil
+}
+
+func (a *App) DeleteTeamType(id string) (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.deleteAIObject("team type", id, ra2.SectionNameTeamTypes, a.aiView().DelTeamType)
+}
+
+func (a *App) ListAITriggerTypes() (_ []*ra2.AITriggerType, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	triggers, err := a.aiView().AITriggerTypes()
+	if err != nil {
+		return nil, NewAppErrorf(ErrorCodeParse, "list ai trigger types error: %v", err)
+	}
+	return nonNil(triggers), nil
+}
+
+// SaveAITriggerType 新建或更新 AITriggerType，ID 为空时分配新 ID。
+// 引用的小队必须存在，Team2 可以为 <none>。
+func (a *App) SaveAITriggerType(t *ra2.AITriggerType) (_ *ra2.AITriggerType, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ai := a.aiView()
+	for _, team := range []string{t.Team1, t.Team2} {
+		if team == "" || team == ra2.NoneValue {
+			continue
+		}
+		if tt, _ := ai.TeamType(team); tt == nil {
+			return nil, NewAppErrorf(ErrorCodeValidation, "team type %s not found", team)
+		}
+	}
+	if t.Team1 == "" || t.Team1 == ra2.NoneValue {
+		return nil, NewAppError(ErrorCodeValidation, "team1 is required")
+	}
+	if t.ID == "" {
+		t.ID = ai.NewID()
+	}
+	err = a.transact("save ai trigger "+t.ID, a.ai, []string{string(ra2.SectionNameAITriggerTypes)}, func() error {
+		if err := ai.SetAITriggerType(t); err != nil {
+			return NewAppErrorf(ErrorCodeValidation, "save ai trigger error: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (a *App) DeleteAITriggerType(id string) (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.transact("delete ai trigger "+id, a.ai, []string{string(ra2.SectionNameAITriggerTypes)}, func() error {
+		if err := a.aiView().DelAITriggerType(id); err != nil {
+			return NewAppErrorf(ErrorCodeNotFound, "delete ai trigger error: %v", err)
+		}
+		return nil
+	})
+}
+
+// ValidateAI 检查 AI 文件中的引用，TaskForce 的成员按合并后的规则检查。
+func (a *App) ValidateAI() (_ []ra2.Problem, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	r, err := a.getRules()
+	if err != nil {
+		return nil, err
+	}
+	return nonNil(a.aiView().Validate(r)), nil
+}
+
+// deleteAIObject 删除注册在 list 中的对象，仍被其他对象引用时拒绝删除。
+func (a *App) deleteAIObject(kind, id string, list ra2.SectionName, del func(id string) error) error {
+	refs, err := a.aiView().References(id)
+	if err != nil {
+		return NewAppErrorf(ErrorCodeInternal, "find references error: %v", err)
+	}
+	if len(refs) > 0 {
+		return NewAppErrorf(ErrorCodeConflict, "%s %s is referenced by %s", kind, id, strings.Join(refs, ", "))
+	}
+	return a.transact("delete "+kind+" "+id, a.ai, []string{string(list), id}, func() error {
+		if err := del(id); err != nil {
+			return NewAppErrorf(ErrorCodeNotFound, "delete %s error: %v", kind, err)
+		}
+		return nil
+	})
+}
+
+// nonNil 避免向前端返回 null。
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return make([]T, 0)
+	}
+	return s
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"ra2-ini-editor/internal/ra2"
+)
+
+// PreviewBulkEdit 返回批量编辑将修改的 section 及修改前后的值，不做任何修改。
+func (a *App) PreviewBulkEdit(edit *ra2.BulkEdit) (_ []ra2.BulkChange, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.planBulkEdit(edit)
+}
+
+// ApplyBulkEdit 将批量编辑写入目标层，作为一次可撤销的修改，返回实际的修改。
+func (a *App) ApplyBulkEdit(edit *ra2.BulkEdit) (_ []ra2.BulkChange, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	target, _, err := a.targetRules()
+	if err != nil {
+		return nil, err
+	}
+	changes, err := a.planBulkEdit(edit)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return changes, nil
+	}
+	below, err := a.layers.Below(target.ID)
+	if err != nil {
+		return nil, NewAppErrorf(ErrorCodeInternal, "merge layers error: %v", err)
+	}
+
+	sections := make([]string, 0, len(changes))
+	for _, change := range changes {
+		sections = append(sections, change.Section)
+	}
+	label := fmt.Sprintf("bulk %s %s on %d sections", edit.Op, edit.Key, len(changes))
+	err = a.transact(label, target, sections, func() error {
+		target.Rules.ApplyBulk(below, changes)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (a *App) planBulkEdit(edit *ra2.BulkEdit) ([]ra2.BulkChange, error) {
+	_, r, err := a.targetRules()
+	if err != nil {
+		return nil, err
+	}
+	changes, err := edit.Plan(r, a.schema)
+	if err != nil {
+		return nil, NewAppErrorf(ErrorCodeValidation, "bulk edit error: %v", err)
+	}
+	return nonNil(changes), nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"ra2-ini-editor/internal/ra2"
+)
+
+// CombatMatrix 计算 attackers 与 defenders 两两之间的 DPS 与击杀时间，level 为 rookie、veteran 或 elite。
+func (a *App) CombatMatrix(attackers, defenders []string, attackerLevel, defenderLevel string) (_ *ra2.CombatMatrix, err error) {
+	defer a.recoverPanic(&err)
+	attackerVet, ok := ra2.NewVeterancy(attackerLevel)
+	if !ok {
+		return nil, NewAppErrorf(ErrorCodeValidation, "unknown veterancy %q", attackerLevel)
+	}
+	defenderVet, ok := ra2.NewVeterancy(defenderLevel)
+	if !ok {
+		return nil, NewAppErrorf(ErrorCodeValidation, "unknown veterancy %q", defenderLevel)
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	r, err := a.getRules()
+	if err != nil {
+		return nil, err
+	}
+	attackerUnits, err := lookupUnits(r, attackers)
+	if err != nil {
+		return nil, err
+	}
+	defenderUnits, err := lookupUnits(r, defenders)
+	if err != nil {
+		return nil, err
+	}
+	return r.Combat(attackerUnits, defenderUnits, attackerVet, defenderVet), nil
+}
+
+func lookupUnits(r *ra2.Rules, names []string) ([]*ra2.Unit, error) {
+	units := make([]*ra2.Unit, 0, len(names))
+	for _, name := range names {
+		unit := r.UnitByName(name)
+		if unit == nil {
+			return nil, NewAppErrorf(ErrorCodeNotFound, "unit %s not found", name)
+		}
+		units = append(units, unit)
+	}
+	return units, nil
+}
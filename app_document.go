@@ -0,0 +1,114 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"ra2-ini-editor/internal/fileutil"
+	"ra2-ini-editor/internal/ra2"
+	"ra2-ini-editor/internal/workspace"
+)
+
+// Document 是不参与规则栈合并、单独编辑的文件，如 aimd.ini、soundmd.ini。
+type Document struct {
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+	Dirty bool   `json:"dirty"`
+}
+
+func newDocument(layer *ra2.Layer) *Document {
+	return &Document{
+		Name:  layer.Name,
+		Path:  layer.Path,
+		Dirty: layer.Dirty,
+	}
+}
+
+// documents 返回所有单独编辑的文件。
+func (a *App) documents() []*ra2.Layer {
+	return []*ra2.Layer{a.ai, a.sound, a.eva}
+}
+
+// documentSlots 按游戏文件名返回单独编辑的层的位置，用于自动保存与恢复。
+func (a *App) documentSlots() map[string]**ra2.Layer {
+	return map[string]**ra2.Layer{
+		ra2.GameFileAI:    &a.ai,
+		ra2.GameFileSound: &a.sound,
+		ra2.GameFileEVA:   &a.eva,
+	}
+}
+
+// openDocument 选择一个 INI 文件，加载后替换 doc 指向的单独编辑的层。
+func (a *App) openDocument(title string, doc **ra2.Layer) error {
+	filename, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: title,
+		Filters: []runtime.FileFilter{
+			{Pattern: "*.ini", DisplayName: "INI Files (*.ini)"},
+		},
+	})
+	if err != nil {
+		return NewAppErrorf(ErrorCodeIO, "open file dialog error: %v", err)
+	}
+	if filename == "" {
+		return NewAppError(ErrorCodeValidation, "no file selected")
+	}
+
+	rules, err := workspace.LoadRules(filename)
+	if err != nil {
+		return newLoadError(err, "load %s error", filepath.Base(filename))
+	}
+	layer := ra2.NewLayer(filepath.Base(filename), rules)
+	layer.Path = filename
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	*doc = layer
+	a.revision++
+	a.addRecentFile(filename)
+	return nil
+}
+
+// saveDocument 将 doc 指向的层保存到其关联的文件，没有关联文件时选择一个文件。
+func (a *App) saveDocument(doc **ra2.Layer, title string) error {
+	a.mu.RLock()
+	layer := *doc
+	filename, defaultFilename := layer.Path, layer.Name
+	a.mu.RUnlock()
+
+	if filename == "" {
+		var err error
+		filename, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			Title:           title,
+			DefaultFilename: defaultFilename,
+			Filters: []runtime.FileFilter{
+				{Pattern: "*.ini", DisplayName: "INI Files (*.ini)"},
+			},
+		})
+		if err != nil {
+			return NewAppErrorf(ErrorCodeIO, "save file dialog error: %v", err)
+		}
+		if filename == "" {
+			return NewAppError(ErrorCodeValidation, "no file selected")
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if *doc != layer {
+		return NewAppErrorf(ErrorCodeConflict, "%s has been replaced", layer.Name)
+	}
+	content, err := layer.Rules.Content()
+	if err != nil {
+		return NewAppErrorf(ErrorCodeInternal, "get %s content error: %v", layer.Name, err)
+	}
+	if err := fileutil.WriteFileAtomic(filename, content, true); err != nil {
+		return NewAppErrorf(ErrorCodeIO, "save %s error: %v", layer.Name, err)
+	}
+	layer.Name = filepath.Base(filename)
+	layer.Path = filename
+	layer.Dirty = false
+	a.revision++
+	a.addRecentFile(filename)
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"ra2-ini-editor/internal/fileutil"
+	"ra2-ini-editor/internal/ra2"
+	"ra2-ini-editor/internal/sheet"
+)
+
+// EconomyReport 是一组单位的经济计算结果，Power 为其中建筑各一座时的电力收支。
+type EconomyReport struct {
+	Rows  []ra2.EconomyRow `json:"rows"`
+	Power ra2.PowerBalance `json:"power"`
+}
+
+// Economy 计算 units 的建造时间与性价比，units 为空时计算所有单位。
+func (a *App) Economy(units []string, opts ra2.EconomyOptions) (_ *EconomyReport, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.economy(units, opts)
+}
+
+// ExportEconomy 将 Economy 的结果导出为 CSV 或 XLSX，按选择的扩展名决定格式。
+func (a *App) ExportEconomy(units []string, opts ra2.EconomyOptions) (err error) {
+	defer a.recoverPanic(&err)
+	filename, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "导出经济数据",
+		DefaultFilename: "economy.xlsx",
+		Filters:         sheetFileFilters,
+	})
+	if err != nil {
+		return NewAppErrorf(ErrorCodeIO, "save file dialog error: %v", err)
+	}
+	if filename == "" {
+		return NewAppError(ErrorCodeValidation, "no file selected")
+	}
+	format, ok := sheet.FormatOf(filename)
+	if !ok {
+		return NewAppErrorf(ErrorCodeValidation, "unsupported sheet format %s", filepath.Ext(filename))
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	report, err := a.economy(units, opts)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := sheet.Write(&buf, format, sheet.EconomyTable(report.Rows)); err != nil {
+		return NewAppErrorf(ErrorCodeInternal, "export sheet error: %v", err)
+	}
+	if err := fileutil.WriteFileAtomic(filename, buf.Bytes(), true); err != nil {
+		return NewAppErrorf(ErrorCodeIO, "save sheet error: %v", err)
+	}
+	return nil
+}
+
+func (a *App) economy(units []string, opts ra2.EconomyOptions) (*EconomyReport, error) {
+	if opts.Armor != "" && !ra2.IsArmorType(opts.Armor) {
+		return nil, NewAppErrorf(ErrorCodeValidation, "unknown armor %q, expected one of %s",
+			opts.Armor, strings.Join(ra2.ArmorTypes, ", "))
+	}
+	r, err := a.getRules()
+	if err != nil {
+		return nil, err
+	}
+	selected := r.Units()
+	if len(units) > 0 {
+		if selected, err = lookupUnits(r, units); err != nil {
+			return nil, err
+		}
+	}
+	buildings := lo.Filter(selected, func(u *ra2.Unit, _ int) bool { return u.Type == ra2.UnitTypeBuilding })
+	return &EconomyReport{
+		Rows:  nonNil(r.Economy(selected, opts)),
+		Power: r.PowerBalance(buildings),
+	}, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/pkg/errors"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"ra2-ini-editor/internal/ra2"
+)
+
+// ErrorCode 是 AppError 的错误类型，沿用 HTTP 状态码便于前端区分处理。
+type ErrorCode int
+
+const (
+	ErrorCodeValidation ErrorCode = 400 // 参数无效或用户取消了选择
+	ErrorCodeNotFound   ErrorCode = 404
+	ErrorCodeConflict   ErrorCode = 409 // 与当前状态冲突，如对象仍被引用、层已被移除
+	ErrorCodeParse      ErrorCode = 422 // 文件格式错误，File 与 Line 指出出错位置
+	ErrorCodeInternal   ErrorCode = 500
+	ErrorCodeIO         ErrorCode = 503 // 读写文件或打开对话框失败
+)
+
+// AppError 是所有绑定方法返回的错误。
+type AppError struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+	File    string    `json:"file,omitempty"`
+	Line    int       `json:"line,omitempty"`
+}
+
+func NewAppError(code ErrorCode, msg string) *AppError {
+	return &AppError{
+		Code:    code,
+		Message: msg,
+	}
+}
+
+func NewAppErrorf(code ErrorCode, format string, args ...interface{}) *AppError {
+	return &AppError{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// newLoadError 将读取文件的错误转换为 AppError，INI 格式错误带有文件名与行号。
+func newLoadError(err error, format string, args ...interface{}) *AppError {
+	e := NewAppErrorf(ErrorCodeIO, format+": %v", append(args, err)...)
+	var parseErr *ra2.ParseError
+	if errors.As(err, &parseErr) {
+		e.Code = ErrorCodeParse
+		e.File = parseErr.File
+		e.Line = parseErr.Line
+	}
+	return e
+}
+
+func (e *AppError) Error() string {
+	return fmt.Sprintf("code=%d, message=%s", e.Code, e.Message)
+}
+
+// recoverPanic 将绑定方法中的 panic 转换为 AppError，需要在方法入口处 defer 调用。
+func (a *App) recoverPanic(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if a.ctx != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("panic: %v", r))
+		runtime.LogError(a.ctx, string(debug.Stack()))
+	}
+	*err = NewAppErrorf(ErrorCodeInternal, "internal error: %v", r)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"ra2-ini-editor/internal/ra2"
+	"ra2-ini-editor/internal/workspace"
+)
+
+// GameFiles 是当前使用的基础数据文件，为空表示使用内置数据或没有该文件。
+type GameFiles struct {
+	Dir   string `json:"dir"`
+	Rules string `json:"rules"`
+	Art   string `json:"art"`
+	AI    string `json:"ai"`
+	Sound string `json:"sound"`
+	EVA   string `json:"eva"`
+	CSF   string `json:"csf"`
+}
+
+func (a *App) GetGameFiles() (_ *GameFiles, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return &GameFiles{
+		Dir:   a.project.Resolve(a.project.GameDir),
+		Rules: a.gameFiles.Rules,
+		Art:   a.gameFiles.Art,
+		AI:    a.gameFiles.AI,
+		Sound: a.gameFiles.Sound,
+		EVA:   a.gameFiles.EVA,
+		CSF:   a.gameFiles.CSF,
+	}, nil
+}
+
+// SetGameDir 选择游戏安装目录或解包目录，并从中重新加载基础数据。
+func (a *App) SetGameDir() (err error) {
+	defer a.recoverPanic(&err)
+	dir, err := runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "选择游戏目录",
+	})
+	if err != nil {
+		return NewAppErrorf(ErrorCodeIO, "open directory dialog error: %v", err)
+	}
+	if dir == "" {
+		return NewAppError(ErrorCodeValidation, "no directory selected")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.setGameDir(dir)
+}
+
+// ClearGameDir 不再使用游戏目录，恢复使用内置数据。
+func (a *App) ClearGameDir() (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.setGameDir("")
+}
+
+// setGameDir 只替换基础层、翻译和单独编辑的文件，保留其他层中未保存的修改。
+func (a *App) setGameDir(dir string) error {
+	p := *a.project
+	p.GameDir = dir
+	files, err := workspace.ResolveGameFiles(&p)
+	if err != nil {
+		return newLoadError(err, "resolve game files error")
+	}
+	translation, origin, err := workspace.LoadBaseData(files, p.Language)
+	if err != nil {
+		return newLoadError(err, "load base data error")
+	}
+
+	// 单独编辑的文件有未保存的修改时保留
+	docs := []**ra2.Layer{&a.ai, &a.sound, &a.eva}
+	loaded := make([]*ra2.Layer, len(docs))
+	for i, load := range []func(*ra2.GameFiles) (*ra2.Layer, error){
+		workspace.LoadAI,
+		workspace.LoadSound,
+		workspace.LoadEVA,
+	} {
+		if (*docs[i]).Dirty {
+			continue
+		}
+		if loaded[i], err = load(files); err != nil {
+			return newLoadError(err, "load game files error")
+		}
+	}
+
+	base := a.baseLayer()
+	if base == nil {
+		return NewAppError(ErrorCodeInternal, "base layer not found")
+	}
+	base.Rules = origin
+	a.layers.Invalidate()
+	for i, layer := range loaded {
+		if layer != nil {
+			*docs[i] = layer
+		}
+	}
+	a.project.GameDir = dir
+	a.gameFiles = files
+	a.translation = translation
+	a.revision++
+	return nil
+}
+
+// baseLayer 返回最底层的只读基础层。
+func (a *App) baseLayer() *ra2.Layer {
+	layers := a.layers.Layers()
+	if len(layers) == 0 || !layers[0].ReadOnly {
+		return nil
+	}
+	return layers[0]
+}
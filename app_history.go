@@ -0,0 +1,138 @@
+package main
+
+import (
+	"slices"
+
+	"ra2-ini-editor/internal/history"
+	"ra2-ini-editor/internal/ra2"
+)
+
+// MaxHistorySize 是撤销历史保留的最大记录数
+const MaxHistorySize = 100
+
+type HistoryEntry struct {
+	Label string `json:"label"`
+	Done  bool   `json:"done"`
+}
+
+// transact 在 layer 上执行 fn 并记录为一次可撤销的修改，fn 失败时回滚。
+// sections 为 fn 会修改的 section，为空时记录整个层。
+func (a *App) transact(label string, layer *ra2.Layer, sections []string, fn func() error) error {
+	before, err := layer.Rules.Snapshot(sections...)
+	if err != nil {
+		return NewAppErrorf(ErrorCodeInternal, "snapshot rules error: %v", err)
+	}
+	if err := fn(); err != nil {
+		if rErr := layer.Rules.Restore(before); rErr != nil {
+			return NewAppErrorf(ErrorCodeInternal, "restore rules error: %v", rErr)
+		}
+		return err
+	}
+	a.markDirty(layer)
+	after, err := layer.Rules.Snapshot(sections...)
+	if err != nil {
+		return NewAppErrorf(ErrorCodeInternal, "snapshot rules error: %v", err)
+	}
+
+	restore := func(snap *ra2.Snapshot) func() error {
+		return func() error {
+			if !a.hasLayer(layer) {
+				return NewAppErrorf(ErrorCodeConflict, "layer %s has been removed", layer.Name)
+			}
+			if err := layer.Rules.Restore(snap); err != nil {
+				return err
+			}
+			a.markDirty(layer)
+			return nil
+		}
+	}
+	a.history.Push(history.NewCommand(label, restore(before), restore(after)))
+	return nil
+}
+
+// layerFile 是层关联的文件，与层的内容一起撤销，撤销打开文件后保存不会写入刚打开的文件。
+type layerFile struct {
+	name  string
+	path  string
+	dirty bool
+	m     *ra2.MapFile
+}
+
+func fileOf(layer *ra2.Layer) layerFile {
+	return layerFile{name: layer.Name, path: layer.Path, dirty: layer.Dirty, m: layer.Map}
+}
+
+// transactFile 将 layer 的内容替换为 snap 并关联到 file，记录为一次可撤销的修改。
+func (a *App) transactFile(label string, layer *ra2.Layer, snap *ra2.Snapshot, file layerFile) error {
+	before, err := layer.Rules.Snapshot()
+	if err != nil {
+		return NewAppErrorf(ErrorCodeInternal, "snapshot rules error: %v", err)
+	}
+	beforeFile := fileOf(layer)
+
+	apply := func(snap *ra2.Snapshot, file layerFile) func() error {
+		return func() error {
+			if !a.hasLayer(layer) {
+				return NewAppErrorf(ErrorCodeConflict, "layer %s has been removed", layer.Name)
+			}
+			if err := layer.Rules.Restore(snap); err != nil {
+				return err
+			}
+			layer.Name = file.name
+			layer.Path = file.path
+			layer.Dirty = file.dirty
+			layer.Map = file.m
+			a.layers.Invalidate()
+			a.revision++
+			return nil
+		}
+	}
+	if err := apply(snap, file)(); err != nil {
+		if rErr := layer.Rules.Restore(before); rErr != nil {
+			return NewAppErrorf(ErrorCodeInternal, "restore rules error: %v", rErr)
+		}
+		return err
+	}
+	a.history.Push(history.NewCommand(label, apply(before, beforeFile), apply(snap, file)))
+	return nil
+}
+
+// hasLayer 返回 layer 是否仍在使用，即在规则栈中或为当前单独编辑的文件。
+func (a *App) hasLayer(layer *ra2.Layer) bool {
+	return a.layers.Layer(layer.ID) != nil || slices.Contains(a.documents(), layer)
+}
+
+func (a *App) Undo() (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.history.Undo(); err != nil {
+		return NewAppErrorf(ErrorCodeValidation, "undo error: %v", err)
+	}
+	return nil
+}
+
+func (a *App) Redo() (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.history.Redo(); err != nil {
+		return NewAppErrorf(ErrorCodeValidation, "redo error: %v", err)
+	}
+	return nil
+}
+
+// History 按时间顺序返回修改记录，已撤销的记录排在最后。
+func (a *App) History() (_ []HistoryEntry, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	entries := make([]HistoryEntry, 0)
+	for _, entry := range a.history.Entries() {
+		entries = append(entries, HistoryEntry{
+			Label: entry.Label,
+			Done:  entry.Done,
+		})
+	}
+	return entries, nil
+}
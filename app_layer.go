@@ -0,0 +1,140 @@
+package main
+
+import (
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"ra2-ini-editor/internal/ra2"
+	"ra2-ini-editor/internal/workspace"
+)
+
+// layerFileFilters 是可以作为覆盖层打开的文件
+var layerFileFilters = []runtime.FileFilter{
+	{Pattern: "*.ini", DisplayName: "INI Files (*.ini)"},
+	{Pattern: "*.map;*.yrm;*.mpr", DisplayName: "Map Files (*.map;*.yrm;*.mpr)"},
+}
+
+type Layer struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Enabled  bool   `json:"enabled"`
+	ReadOnly bool   `json:"read_only"`
+	Target   bool   `json:"target"`
+	Map      bool   `json:"map"` // 从地图文件打开的层
+}
+
+// ListLayers 按从底到顶的顺序返回规则栈中的所有层。
+func (a *App) ListLayers() (_ []*Layer, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	target := a.layers.Target()
+	layers := make([]*Layer, 0)
+	for _, layer := range a.layers.Layers() {
+		layers = append(layers, &Layer{
+			ID:       layer.ID,
+			Name:     layer.Name,
+			Path:     layer.Path,
+			Enabled:  layer.Enabled,
+			ReadOnly: layer.ReadOnly,
+			Target:   target != nil && target.ID == layer.ID,
+			Map:      layer.Map != nil,
+		})
+	}
+	return layers, nil
+}
+
+// AddLayer 选择一个 INI 文件或地图文件并作为新层压入栈顶。
+func (a *App) AddLayer() (err error) {
+	defer a.recoverPanic(&err)
+	filename, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:   "选择一个文件",
+		Filters: layerFileFilters,
+	})
+	if err != nil {
+		return NewAppErrorf(ErrorCodeIO, "open file dialog error: %v", err)
+	}
+	if filename == "" {
+		return NewAppError(ErrorCodeValidation, "no file selected")
+	}
+
+	layer, err := workspace.LoadLayer(filename)
+	if err != nil {
+		return newLoadError(err, "load rules error")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.layers.Add(layer); err != nil {
+		return NewAppErrorf(ErrorCodeInternal, "add layer error: %v", err)
+	}
+	a.revision++
+	a.addRecentFile(filename)
+	return nil
+}
+
+// NewLayer 在栈顶新建一个空层。
+func (a *App) NewLayer(name string) (_ *Layer, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if name == "" {
+		return nil, NewAppError(ErrorCodeValidation, "layer name is empty")
+	}
+	layer := ra2.NewLayer(name, ra2.NewEmptyRules())
+	if err := a.layers.Add(layer); err != nil {
+		return nil, NewAppErrorf(ErrorCodeInternal, "add layer error: %v", err)
+	}
+	a.revision++
+	return &Layer{
+		ID:      layer.ID,
+		Name:    layer.Name,
+		Enabled: layer.Enabled,
+	}, nil
+}
+
+func (a *App) RemoveLayer(id string) (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.layers.Remove(id); err != nil {
+		return NewAppErrorf(ErrorCodeValidation, "remove layer error: %v", err)
+	}
+	a.revision++
+	return nil
+}
+
+// MoveLayer 将层移动到 index 位置，index 按从底到顶计数。
+func (a *App) MoveLayer(id string, index int) (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.layers.Move(id, index); err != nil {
+		return NewAppErrorf(ErrorCodeValidation, "move layer error: %v", err)
+	}
+	a.revision++
+	return nil
+}
+
+func (a *App) SetLayerEnabled(id string, enabled bool) (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.layers.SetEnabled(id, enabled); err != nil {
+		return NewAppErrorf(ErrorCodeValidation, "set layer enabled error: %v", err)
+	}
+	a.revision++
+	return nil
+}
+
+// SetWriteTarget 指定 SaveUnit、DeleteUnit 等修改写入的层。
+func (a *App) SetWriteTarget(id string) (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.layers.SetTarget(id); err != nil {
+		return NewAppErrorf(ErrorCodeValidation, "set write target error: %v", err)
+	}
+	a.revision++
+	return nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"github.com/samber/lo"
+
+	"ra2-ini-editor/internal/lint"
+	"ra2-ini-editor/internal/project"
+	"ra2-ini-editor/internal/ra2"
+	"ra2-ini-editor/internal/workspace"
+)
+
+// LintRule 是一条 lint 规则的说明，供界面列出与开关规则。
+type LintRule struct {
+	ID       string        `json:"id"`
+	Severity lint.Severity `json:"severity"`
+	Desc     string        `json:"desc"`
+	Disabled bool          `json:"disabled"`
+}
+
+// ListLintRules 返回内置的规则，级别与开关按项目设置。
+func (a *App) ListLintRules() (_ []LintRule, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	opts, err := lint.ProjectOptions(a.project.Lint)
+	if err != nil {
+		return nil, NewAppErrorf(ErrorCodeValidation, "lint settings error: %v", err)
+	}
+	var rules []LintRule
+	for _, rule := range lint.DefaultRules() {
+		severity := rule.Severity
+		if s, ok := opts.Severities[rule.ID]; ok {
+			severity = s
+		}
+		rules = append(rules, LintRule{
+			ID:       rule.ID,
+			Severity: severity,
+			Desc:     rule.Desc,
+			Disabled: lo.Contains(opts.Disabled, rule.ID),
+		})
+	}
+	return rules, nil
+}
+
+// SetLintRule 在项目设置中开关规则 id 并设置其级别，severity 为空时使用默认级别。保存项目后生效于其他成员。
+func (a *App) SetLintRule(id string, enabled bool, severity string) (err error) {
+	defer a.recoverPanic(&err)
+	if !lo.ContainsBy(lint.DefaultRules(), func(r lint.Rule) bool { return r.ID == id }) {
+		return NewAppErrorf(ErrorCodeNotFound, "lint rule %s not found", id)
+	}
+	if _, ok := lint.ParseSeverity(severity); severity != "" && !ok {
+		return NewAppErrorf(ErrorCodeValidation, "unknown severity %q", severity)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	cfg := &a.project.Lint
+	cfg.Disabled = lo.Without(cfg.Disabled, id)
+	if !enabled {
+		cfg.Disabled = append(cfg.Disabled, id)
+	}
+	if severity == "" {
+		delete(cfg.Severities, id)
+	} else {
+		if cfg.Severities == nil {
+			cfg.Severities = make(map[string]string)
+		}
+		cfg.Severities[id] = severity
+	}
+	return nil
+}
+
+// Lint 按项目设置检查合并后的规则。all 为 false 时只报告覆盖层与 AI 文件中的问题，见 lint.LayerScope。
+func (a *App) Lint(all bool) (_ []lint.Issue, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	r, err := a.getRules()
+	if err != nil {
+		return nil, err
+	}
+	opts, err := lint.ProjectOptions(a.project.Lint)
+	if err != nil {
+		return nil, NewAppErrorf(ErrorCodeValidation, "lint settings error: %v", err)
+	}
+	if !all {
+		opts.Scope = lint.LayerScope(a.layers)
+	}
+	ctx := &lint.Context{
+		Rules:       r,
+		Schema:      a.schema,
+		Translation: a.translation,
+		AI:          a.aiView(),
+		Sounds:      ra2.NewSoundList(a.sound.Rules),
+		EVA:         ra2.NewEVAList(a.eva.Rules),
+		Engine:      a.project.Engine,
+	}
+	if ctx.Engine == "" {
+		if ctx.EngineSchema, err = workspace.LoadEngineSchema(project.EngineAres); err != nil {
+			return nil, NewAppErrorf(ErrorCodeInternal, "load engine schema error: %v", err)
+		}
+	}
+	issues, err := lint.Run(ctx, lint.DefaultRules(), opts)
+	if err != nil {
+		return nil, NewAppErrorf(ErrorCodeValidation, "lint error: %v", err)
+	}
+	return nonNil(issues), nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"ra2-ini-editor/internal/fileutil"
+	"ra2-ini-editor/internal/workspace"
+)
+
+// workspace 返回与当前状态对应的 Workspace。
+func (a *App) workspace() *workspace.Workspace {
+	return &workspace.Workspace{
+		Project:     a.currentProject(),
+		GameFiles:   a.gameFiles,
+		Schema:      a.schema,
+		Translation: a.translation,
+		Layers:      a.layers,
+		AI:          a.ai,
+		Sound:       a.sound,
+		EVA:         a.eva,
+	}
+}
+
+// PackageMod 将合并后的规则、编辑过的 AI 等文件及项目中配置的打包文件写成 MIX 文件，见 Workspace.Package。
+func (a *App) PackageMod() (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defaultFilename := workspace.DefaultPackageName
+	if output := a.project.Package.Output; output != "" {
+		defaultFilename = filepath.Base(output)
+	}
+	a.mu.RUnlock()
+
+	filename, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "打包 MOD",
+		DefaultFilename: defaultFilename,
+		Filters: []runtime.FileFilter{
+			{Pattern: "*.mix", DisplayName: "MIX Files (*.mix)"},
+		},
+	})
+	if err != nil {
+		return NewAppErrorf(ErrorCodeIO, "save file dialog error: %v", err)
+	}
+	if filename == "" {
+		return NewAppError(ErrorCodeValidation, "no file selected")
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	var buf bytes.Buffer
+	if err := a.workspace().Package(&buf); err != nil {
+		return NewAppErrorf(ErrorCodeIO, "package mod error: %v", err)
+	}
+	if err := fileutil.WriteFileAtomic(filename, buf.Bytes(), true); err != nil {
+		return NewAppErrorf(ErrorCodeIO, "write package error: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"ra2-ini-editor/internal/project"
+	"ra2-ini-editor/internal/workspace"
+)
+
+// loadProject 按项目设置重新加载 schema、翻译和规则栈。
+func (a *App) loadProject(p *project.Project) error {
+	ws, err := workspace.Load(p)
+	if err != nil {
+		return newLoadError(err, "load project error")
+	}
+
+	a.project = ws.Project
+	a.gameFiles = ws.GameFiles
+	a.schema = ws.Schema
+	a.translation = ws.Translation
+	a.layers = ws.Layers
+	a.ai = ws.AI
+	a.sound = ws.Sound
+	a.eva = ws.EVA
+	a.history.Clear()
+	a.revision++
+	return nil
+}
+
+// currentProject 返回包含当前规则栈的项目设置。
+func (a *App) currentProject() *project.Project {
+	p := *a.project
+	p.Layers = nil
+	target := a.layers.Target()
+	for _, layer := range a.layers.Layers() {
+		if layer.ReadOnly {
+			continue
+		}
+		p.Layers = append(p.Layers, project.Layer{
+			Name:    layer.Name,
+			Path:    layer.Path,
+			Enabled: layer.Enabled,
+			Target:  target != nil && target.ID == layer.ID,
+		})
+	}
+	return &p
+}
+
+// NewProject 将当前的基础数据、规则栈与设置保存为新的项目文件。
+// 尚未保存到文件的层在项目中记录为空层。
+func (a *App) NewProject() (err error) {
+	defer a.recoverPanic(&err)
+	filename, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "新建项目",
+		DefaultFilename: "project.json",
+		Filters: []runtime.FileFilter{
+			{Pattern: "*.json", DisplayName: "Project Files (*.json)"},
+		},
+	})
+	if err != nil {
+		return NewAppErrorf(ErrorCodeIO, "save file dialog error: %v", err)
+	}
+	if filename == "" {
+		return NewAppError(ErrorCodeValidation, "no file selected")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.saveProject(filename)
+}
+
+func (a *App) OpenProject() (err error) {
+	defer a.recoverPanic(&err)
+	filename, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "打开项目",
+		Filters: []runtime.FileFilter{
+			{Pattern: "*.json", DisplayName: "Project Files (*.json)"},
+		},
+	})
+	if err != nil {
+		return NewAppErrorf(ErrorCodeIO, "open file dialog error: %v", err)
+	}
+	if filename == "" {
+		return NewAppError(ErrorCodeValidation, "no file selected")
+	}
+
+	p, err := project.Load(filename)
+	if err != nil {
+		return newLoadError(err, "load project error")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.loadProject(p); err != nil {
+		return err
+	}
+	a.projectPath = filename
+	return nil
+}
+
+// SaveProject 保存到当前项目文件，未关联项目文件时等同于 NewProject。
+func (a *App) SaveProject() (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	if a.projectPath != "" {
+		defer a.mu.Unlock()
+		return a.saveProject(a.projectPath)
+	}
+	a.mu.Unlock()
+	return a.NewProject()
+}
+
+func (a *App) saveProject(filename string) error {
+	p := a.currentProject()
+	if err := p.Save(filename); err != nil {
+		return NewAppErrorf(ErrorCodeIO, "save project error: %v", err)
+	}
+	a.project = p
+	a.projectPath = filename
+	return nil
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"ra2-ini-editor/internal/ra2"
+)
+
+// Query 在合并后的规则中查找满足表达式的 section，语法见 ra2.Query。
+func (a *App) Query(expr string) (_ []ra2.QueryResult, err error) {
+	defer a.recoverPanic(&err)
+	q, err := ra2.ParseQuery(expr)
+	if err != nil {
+		return nil, NewAppErrorf(ErrorCodeValidation, "parse query error: %v", err)
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	r, err := a.getRules()
+	if err != nil {
+		return nil, err
+	}
+	return nonNil(r.Query(q, a.schema)), nil
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"ra2-ini-editor/internal/ra2"
+	"ra2-ini-editor/internal/recovery"
+)
+
+// AutosaveInterval 是自动保存的间隔
+const AutosaveInterval = 30 * time.Second
+
+type Recovery struct {
+	SavedAt time.Time `json:"saved_at"`
+	Layers  []string  `json:"layers"` // 有未保存修改的层
+}
+
+// markDirty 标记 layer 有未保存的修改。
+func (a *App) markDirty(layer *ra2.Layer) {
+	layer.Dirty = true
+	a.layers.Invalidate()
+	a.revision++
+}
+
+// HasUnsavedChanges 返回是否有层存在未保存的修改。
+func (a *App) HasUnsavedChanges() (_ bool, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.hasUnsavedChanges(), nil
+}
+
+func (a *App) hasUnsavedChanges() bool {
+	return lo.ContainsBy(append(a.layers.Layers(), a.documents()...), func(l *ra2.Layer) bool {
+		return l.Dirty
+	})
+}
+
+// GetRecovery 返回上次未正常保存的内容，没有时返回 nil。
+func (a *App) GetRecovery() (_ *Recovery, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	m, err := a.recovery.Load()
+	if err != nil {
+		return nil, newLoadError(err, "load recovery error")
+	}
+	if m == nil {
+		return nil, nil
+	}
+	layers := make([]string, 0)
+	for _, layer := range m.Layers {
+		if layer.Dirty {
+			layers = append(layers, layer.Name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.Documents)) {
+		if doc := m.Documents[name]; doc.Dirty {
+			layers = append(layers, doc.Name)
+		}
+	}
+	return &Recovery{
+		SavedAt: m.SavedAt,
+		Layers:  layers,
+	}, nil
+}
+
+// RestoreRecovery 使用自动保存的内容替换当前所有可写层与单独编辑的文件。
+func (a *App) RestoreRecovery() (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	m, err := a.recovery.Load()
+	if err != nil {
+		return newLoadError(err, "load recovery error")
+	}
+	if m == nil {
+		return NewAppError(ErrorCodeNotFound, "no recovery found")
+	}
+
+	layers := make([]*ra2.Layer, 0)
+	for _, saved := range m.Layers {
+		var (
+			m     *ra2.MapFile
+			rules *ra2.Rules
+		)
+		if ra2.IsMapFile(saved.Path) {
+			m, rules, err = ra2.LoadMap(bytes.NewReader(saved.Content))
+		} else {
+			rules, err = ra2.NewRules(io.NopCloser(bytes.NewReader(saved.Content)))
+		}
+		if err != nil {
+			return newLoadError(err, "load recovered layer %s error", saved.Name)
+		}
+		layer := ra2.NewLayer(saved.Name, rules)
+		layer.Map = m
+		layer.ID = saved.ID
+		layer.Path = saved.Path
+		layer.Enabled = saved.Enabled
+		layer.Dirty = saved.Dirty
+		layers = append(layers, layer)
+	}
+
+	docs := make(map[string]*ra2.Layer, len(m.Documents))
+	for name, saved := range m.Documents {
+		rules, err := ra2.NewRules(io.NopCloser(bytes.NewReader(saved.Content)))
+		if err != nil {
+			return newLoadError(err, "load recovered document %s error", saved.Name)
+		}
+		doc := ra2.NewLayer(saved.Name, rules)
+		doc.ID = saved.ID
+		doc.Path = saved.Path
+		doc.Dirty = saved.Dirty
+		docs[name] = doc
+	}
+
+	for _, layer := range a.layers.Layers() {
+		if !layer.ReadOnly {
+			if err := a.layers.Remove(layer.ID); err != nil {
+				return NewAppErrorf(ErrorCodeInternal, "remove layer error: %v", err)
+			}
+		}
+	}
+	for _, layer := range layers {
+		if err := a.layers.Add(layer); err != nil {
+			return NewAppErrorf(ErrorCodeInternal, "add layer error: %v", err)
+		}
+	}
+	if m.Target != "" {
+		if err := a.layers.SetTarget(m.Target); err != nil {
+			return NewAppErrorf(ErrorCodeInternal, "set write target error: %v", err)
+		}
+	}
+	slots := a.documentSlots()
+	for name, doc := range docs {
+		if slot, ok := slots[name]; ok {
+			*slot = doc
+		}
+	}
+	a.history.Clear()
+	a.revision++
+	return nil
+}
+
+// DiscardRecovery 删除自动保存的内容。
+func (a *App) DiscardRecovery() (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.recovery.Clear(); err != nil {
+		return NewAppErrorf(ErrorCodeIO, "clear recovery error: %v", err)
+	}
+	return nil
+}
+
+func (a *App) autosaveLoop(ctx context.Context) {
+	ticker := time.NewTicker(AutosaveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := a.autosave(); err != nil {
+				runtime.LogError(ctx, fmt.Sprintf("autosave error: %v", err))
+			}
+		}
+	}
+}
+
+// autosave 在上次自动保存后有修改时，将所有可写层与单独编辑的文件写入恢复目录。
+func (a *App) autosave() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.revision == a.autosavedRevision {
+		return nil
+	}
+	if !a.hasUnsavedChanges() {
+		// 修改都已保存，不再需要恢复
+		if err := a.recovery.Clear(); err != nil {
+			return err
+		}
+		a.autosavedRevision = a.revision
+		return nil
+	}
+
+	m := &recovery.Manifest{
+		SavedAt: time.Now(),
+	}
+	if target := a.layers.Target(); target != nil {
+		m.Target = target.ID
+	}
+	for _, layer := range a.layers.Layers() {
+		if layer.ReadOnly {
+			continue
+		}
+		content, err := layer.Content()
+		if err != nil {
+			return err
+		}
+		m.Layers = append(m.Layers, recovery.Layer{
+			ID:      layer.ID,
+			Name:    layer.Name,
+			Path:    layer.Path,
+			Enabled: layer.Enabled,
+			Dirty:   layer.Dirty,
+			Content: content,
+		})
+	}
+	for name, slot := range a.documentSlots() {
+		doc := *slot
+		content, err := doc.Rules.Content()
+		if err != nil {
+			return err
+		}
+		if m.Documents == nil {
+			m.Documents = make(map[string]recovery.Layer)
+		}
+		m.Documents[name] = recovery.Layer{
+			ID:      doc.ID,
+			Name:    doc.Name,
+			Path:    doc.Path,
+			Enabled: doc.Enabled,
+			Dirty:   doc.Dirty,
+			Content: content,
+		}
+	}
+	if err := a.recovery.Save(m); err != nil {
+		return err
+	}
+	a.autosavedRevision = a.revision
+	return nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/samber/lo"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"ra2-ini-editor/internal/fileutil"
+	"ra2-ini-editor/internal/ra2"
+	"ra2-ini-editor/internal/sheet"
+)
+
+var sheetFileFilters = []runtime.FileFilter{
+	{Pattern: "*.xlsx", DisplayName: "Excel Files (*.xlsx)"},
+	{Pattern: "*.csv", DisplayName: "CSV Files (*.csv)"},
+}
+
+// SheetImport 是导入表格的预览，有 Problems 时不能导入。
+type SheetImport struct {
+	Path     string           `json:"path"`
+	Changes  []ra2.BulkChange `json:"changes"`
+	Problems []ra2.Problem    `json:"problems"`
+}
+
+// ExportSheet 将 sections 在 keys 上的生效值导出为 CSV 或 XLSX，按选择的扩展名决定格式。
+func (a *App) ExportSheet(sections []string, keys []string) (err error) {
+	defer a.recoverPanic(&err)
+	filename, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "导出表格",
+		DefaultFilename: "units.xlsx",
+		Filters:         sheetFileFilters,
+	})
+	if err != nil {
+		return NewAppErrorf(ErrorCodeIO, "save file dialog error: %v", err)
+	}
+	if filename == "" {
+		return NewAppError(ErrorCodeValidation, "no file selected")
+	}
+	format, ok := sheet.FormatOf(filename)
+	if !ok {
+		return NewAppErrorf(ErrorCodeValidation, "unsupported sheet format %s", filepath.Ext(filename))
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	r, err := a.getRules()
+	if err != nil {
+		return err
+	}
+	for _, section := range sections {
+		if !r.HasSection(section) {
+			return NewAppErrorf(ErrorCodeNotFound, "section %s not found", section)
+		}
+	}
+	var buf bytes.Buffer
+	if err := sheet.Write(&buf, format, sheet.Export(r, sections, keys)); err != nil {
+		return NewAppErrorf(ErrorCodeInternal, "export sheet error: %v", err)
+	}
+	if err := fileutil.WriteFileAtomic(filename, buf.Bytes(), true); err != nil {
+		return NewAppErrorf(ErrorCodeIO, "save sheet error: %v", err)
+	}
+	return nil
+}
+
+// OpenSheet 选择一个表格，返回导入后的修改与问题，不做任何修改。确认后调用 ImportSheet 导入。
+func (a *App) OpenSheet() (_ *SheetImport, err error) {
+	defer a.recoverPanic(&err)
+	filename, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:   "导入表格",
+		Filters: sheetFileFilters,
+	})
+	if err != nil {
+		return nil, NewAppErrorf(ErrorCodeIO, "open file dialog error: %v", err)
+	}
+	if filename == "" {
+		return nil, NewAppError(ErrorCodeValidation, "no file selected")
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.planSheet(filename)
+}
+
+// ImportSheet 将表格 filename 中的值写入目标层，作为一次可撤销的修改，返回实际的修改。
+func (a *App) ImportSheet(filename string) (_ []ra2.BulkChange, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	target, _, err := a.targetRules()
+	if err != nil {
+		return nil, err
+	}
+	plan, err := a.planSheet(filename)
+	if err != nil {
+		return nil, err
+	}
+	if len(plan.Problems) > 0 {
+		first := plan.Problems[0]
+		return nil, NewAppErrorf(ErrorCodeValidation, "sheet has %d problems, first: [%s] %s %s",
+			len(plan.Problems), first.Section, first.Key, first.Message)
+	}
+	if len(plan.Changes) == 0 {
+		return plan.Changes, nil
+	}
+	below, err := a.layers.Below(target.ID)
+	if err != nil {
+		return nil, NewAppErrorf(ErrorCodeInternal, "merge layers error: %v", err)
+	}
+
+	sections := lo.Uniq(lo.Map(plan.Changes, func(c ra2.BulkChange, _ int) string { return c.Section }))
+	err = a.transact("import "+filepath.Base(filename), target, sections, func() error {
+		target.Rules.ApplyBulk(below, plan.Changes)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan.Changes, nil
+}
+
+func (a *App) planSheet(filename string) (*SheetImport, error) {
+	format, ok := sheet.FormatOf(filename)
+	if !ok {
+		return nil, NewAppErrorf(ErrorCodeValidation, "unsupported sheet format %s", filepath.Ext(filename))
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, NewAppErrorf(ErrorCodeIO, "open sheet error: %v", err)
+	}
+	defer f.Close()
+	table, err := sheet.Read(f, format)
+	if err != nil {
+		e := NewAppErrorf(ErrorCodeParse, "read sheet error: %v", err)
+		e.File = filename
+		return nil, e
+	}
+
+	_, r, err := a.targetRules()
+	if err != nil {
+		return nil, err
+	}
+	changes, problems := table.Plan(r, a.schema)
+	return &SheetImport{
+		Path:     filename,
+		Changes:  nonNil(changes),
+		Problems: nonNil(problems),
+	}, nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"ra2-ini-editor/internal/ra2"
+)
+
+func (a *App) GetSoundFile() (_ *Document, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return newDocument(a.sound), nil
+}
+
+// OpenSound 选择一个 soundmd.ini 替换当前的音效文件。
+func (a *App) OpenSound() (err error) {
+	defer a.recoverPanic(&err)
+	return a.openDocument("打开音效文件", &a.sound)
+}
+
+func (a *App) SaveSound() (err error) {
+	defer a.recoverPanic(&err)
+	return a.saveDocument(&a.sound, "保存音效文件")
+}
+
+// ListSounds 返回 [SoundList] 中注册的所有音效，供编辑及 Sound 类型的属性选择。
+func (a *App) ListSounds() (_ []*ra2.SoundEntry, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return nonNil(ra2.NewSoundList(a.sound.Rules).Entries()), nil
+}
+
+// SaveSoundEntry 新建或更新一个音效。
+func (a *App) SaveSoundEntry(entry *ra2.SoundEntry) (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.saveSoundEntry("sound", a.sound, ra2.SectionNameSoundList, ra2.NewSoundList, entry)
+}
+
+func (a *App) DeleteSoundEntry(name string) (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.deleteSoundEntry("sound", a.sound, ra2.SectionNameSoundList, ra2.NewSoundList, name)
+}
+
+func (a *App) GetEVAFile() (_ *Document, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return newDocument(a.eva), nil
+}
+
+// OpenEVA 选择一个 evamd.ini 替换当前的 EVA 语音文件。
+func (a *App) OpenEVA() (err error) {
+	defer a.recoverPanic(&err)
+	return a.openDocument("打开 EVA 语音文件", &a.eva)
+}
+
+func (a *App) SaveEVA() (err error) {
+	defer a.recoverPanic(&err)
+	return a.saveDocument(&a.eva, "保存 EVA 语音文件")
+}
+
+// ListEVAEvents 返回 [DialogList] 中注册的所有 EVA 语音。
+func (a *App) ListEVAEvents() (_ []*ra2.SoundEntry, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return nonNil(ra2.NewEVAList(a.eva.Rules).Entries()), nil
+}
+
+func (a *App) SaveEVAEvent(entry *ra2.SoundEntry) (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.saveSoundEntry("eva event", a.eva, ra2.SectionNameDialogList, ra2.NewEVAList, entry)
+}
+
+func (a *App) DeleteEVAEvent(name string) (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.deleteSoundEntry("eva event", a.eva, ra2.SectionNameDialogList, ra2.NewEVAList, name)
+}
+
+// ValidateSounds 检查合并后的规则中 Sound 与 EVAVoice 类型的属性是否引用了已定义的音效和语音。
+// 未加载音效或 EVA 文件时不检查对应的属性。
+func (a *App) ValidateSounds() (_ []ra2.Problem, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	r, err := a.getRules()
+	if err != nil {
+		return nil, err
+	}
+	problems := ra2.ValidateSounds(r, a.schema, ra2.NewSoundList(a.sound.Rules), ra2.NewEVAList(a.eva.Rules))
+	return nonNil(problems), nil
+}
+
+func (a *App) saveSoundEntry(kind string, layer *ra2.Layer, list ra2.SectionName, view func(*ra2.Rules) *ra2.SoundList, entry *ra2.SoundEntry) error {
+	if entry.Name == "" {
+		return NewAppErrorf(ErrorCodeValidation, "%s name is empty", kind)
+	}
+	return a.transact("save "+kind+" "+entry.Name, layer, []string{string(list), entry.Name}, func() error {
+		if err := view(layer.Rules).Set(entry); err != nil {
+			return NewAppErrorf(ErrorCodeValidation, "save %s error: %v", kind, err)
+		}
+		return nil
+	})
+}
+
+func (a *App) deleteSoundEntry(kind string, layer *ra2.Layer, list ra2.SectionName, view func(*ra2.Rules) *ra2.SoundList, name string) error {
+	return a.transact("delete "+kind+" "+name, layer, []string{string(list), name}, func() error {
+		if err := view(layer.Rules).Del(name); err != nil {
+			return NewAppErrorf(ErrorCodeNotFound, "delete %s error: %v", kind, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/samber/lo"
+
+	"ra2-ini-editor/internal/ra2"
+)
+
+// UnitTable 是多个单位在若干 key 上的生效值，按列组织，每列的 Cells 与 Units 一一对应。
+type UnitTable struct {
+	Units   []string      `json:"units"`
+	UINames []string      `json:"ui_names"`
+	Columns []TableColumn `json:"columns"`
+}
+
+type TableColumn struct {
+	Key   string      `json:"key"`
+	Cells []TableCell `json:"cells"`
+}
+
+type TableCell struct {
+	Value string `json:"value"`
+	// 没有任何层设置该 key 时为 true，Value 为游戏默认值
+	Default bool `json:"default"`
+
+	// 提供生效值的层
+	SourceID string `json:"source_id"`
+	Source   string `json:"source"`
+}
+
+// TableEdit 是表格中修改的一个单元格。
+type TableEdit struct {
+	Unit  string `json:"unit"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// GetUnitTable 返回 units 在 keys 上的生效值及来源，units 为单位的注册名。
+func (a *App) GetUnitTable(units []string, keys []string) (_ *UnitTable, err error) {
+	defer a.recoverPanic(&err)
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	r, err := a.getRules()
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]*ra2.Unit, len(units))
+	table := &UnitTable{
+		Units:   units,
+		UINames: make([]string, len(units)),
+		Columns: make([]TableColumn, len(keys)),
+	}
+	for i, name := range units {
+		unit := r.UnitByName(name)
+		if unit == nil {
+			return nil, NewAppErrorf(ErrorCodeNotFound, "unit %s not found", name)
+		}
+		resolved[i] = unit
+		table.UINames[i] = a.translation.Get(unit.UIName())
+	}
+	for i, key := range keys {
+		column := TableColumn{
+			Key:   key,
+			Cells: make([]TableCell, len(units)),
+		}
+		for j, unit := range resolved {
+			cell := &column.Cells[j]
+			if source := a.layers.Source(unit.Name, key); source != nil {
+				cell.Value = unit.Get(key)
+				cell.SourceID = source.ID
+				cell.Source = source.Name
+			} else {
+				cell.Value, _ = a.schema.DefaultValue(unit.Type, key)
+				cell.Default = true
+			}
+		}
+		table.Columns[i] = column
+	}
+	return table, nil
+}
+
+// SaveUnitTable 将表格中修改的单元格写入目标层，作为一次可撤销的修改。
+// 与下层相同的值不写入目标层。
+func (a *App) SaveUnitTable(edits []TableEdit) (err error) {
+	defer a.recoverPanic(&err)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(edits) == 0 {
+		return nil
+	}
+	target, r, err := a.targetRules()
+	if err != nil {
+		return err
+	}
+	below, err := a.layers.Below(target.ID)
+	if err != nil {
+		return NewAppErrorf(ErrorCodeInternal, "merge layers error: %v", err)
+	}
+
+	changes := make([]ra2.BulkChange, 0, len(edits))
+	sections := make([]string, 0, len(edits))
+	for _, edit := range edits {
+		if edit.Key == "" {
+			return NewAppErrorf(ErrorCodeValidation, "key of unit %s is empty", edit.Unit)
+		}
+		unit := r.UnitByName(edit.Unit)
+		if unit == nil {
+			return NewAppErrorf(ErrorCodeNotFound, "unit %s not found", edit.Unit)
+		}
+		changes = append(changes, ra2.BulkChange{
+			Section: unit.Name,
+			Type:    unit.Type,
+			Key:     edit.Key,
+			Old:     unit.Get(edit.Key),
+			New:     edit.Value,
+		})
+		sections = append(sections, unit.Name)
+	}
+	label := fmt.Sprintf("edit %d table cells", len(edits))
+	return a.transact(label, target, lo.Uniq(sections), func() error {
+		target.Rules.ApplyBulk(below, changes)
+		return nil
+	})
+}
@@ -0,0 +1,553 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ra2-ini-editor/internal/lint"
+	"ra2-ini-editor/internal/ra2"
+)
+
+func newTestApp(t *testing.T) *App {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	a, err := NewApp()
+	require.NoError(t, err)
+	return a
+}
+
+// TestApp_Concurrent 同时读取和修改状态，需要配合 -race 运行。
+func TestApp_Concurrent(t *testing.T) {
+	a := newTestApp(t)
+	layer, err := a.NewLayer("patch")
+	require.NoError(t, err)
+
+	const workers = 3
+	const rounds = 5
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				_, err := a.ListAllUnits()
+				assert.NoError(t, err)
+				unit, err := a.GetUnit(string(ra2.UnitTypeVehicle), 4)
+				assert.NoError(t, err)
+				assert.Equal(t, "HTNK", unit.Name)
+				_, err = a.ListLayers()
+				assert.NoError(t, err)
+				_, err = a.History()
+				assert.NoError(t, err)
+				_, err = a.UserRules()
+				assert.NoError(t, err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				unit, err := a.GetUnit(string(ra2.UnitTypeVehicle), 4)
+				assert.NoError(t, err)
+				for j := range unit.Properties {
+					if unit.Properties[j].Key == "Cost" {
+						unit.Properties[j].Value = fmt.Sprint(900 + i)
+					}
+				}
+				assert.NoError(t, a.SaveUnit(unit))
+				// 其他 goroutine 可能已经撤销了所有修改
+				_ = a.Undo()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				assert.NoError(t, a.SetLayerEnabled(layer.ID, i%2 == 0))
+				_, err := a.HasUnsavedChanges()
+				assert.NoError(t, err)
+				assert.NoError(t, a.autosave())
+			}
+		}()
+	}
+	wg.Wait()
+
+	unit, err := a.GetUnit(string(ra2.UnitTypeVehicle), 4)
+	require.NoError(t, err)
+	assert.Equal(t, "HTNK", unit.Name)
+}
+
+func TestApp_RecoverPanic(t *testing.T) {
+	a := newTestApp(t)
+
+	// nil 单位会在方法内部解引用时 panic
+	err := a.SaveUnit(nil)
+	var appErr *AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, ErrorCodeInternal, appErr.Code)
+
+	// panic 后锁已释放
+	_, err = a.ListAllUnits()
+	assert.NoError(t, err)
+}
+
+func TestNewLoadError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want *AppError
+	}{
+		{
+			name: "io",
+			err:  os.ErrNotExist,
+			want: &AppError{Code: ErrorCodeIO, Message: "load rules error: file does not exist"},
+		},
+		{
+			name: "parse",
+			err:  errors.WithStack(&ra2.ParseError{File: "rulesmd.ini", Line: 3, Err: errors.New("unclosed section: [E1")}),
+			want: &AppError{
+				Code:    ErrorCodeParse,
+				Message: "load rules error: rulesmd.ini:3: unclosed section: [E1",
+				File:    "rulesmd.ini",
+				Line:    3,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newLoadError(tt.err, "load rules error"))
+		})
+	}
+}
+
+func TestApp_Query(t *testing.T) {
+	a := newTestApp(t)
+
+	results, err := a.Query("type:vehicle and Armor=heavy and Speed>6 and Owner=Russians")
+	require.NoError(t, err)
+	assert.NotEmpty(t, results)
+	for _, r := range results {
+		assert.Equal(t, ra2.UnitTypeVehicle, r.Type)
+		assert.Contains(t, r.Values, "Speed")
+	}
+
+	_, err = a.Query("Speed>")
+	var appErr *AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, ErrorCodeValidation, appErr.Code)
+}
+
+func TestApp_BulkEdit(t *testing.T) {
+	a := newTestApp(t)
+	edit := &ra2.BulkEdit{Query: "type:vehicle and Owner=Russians", Op: ra2.BulkOpScale, Key: "Cost", Value: "1.1"}
+	cost := func(section string) string {
+		r, err := a.getRules()
+		require.NoError(t, err)
+		return r.UnitByName(section).Get("Cost")
+	}
+
+	preview, err := a.PreviewBulkEdit(edit)
+	require.NoError(t, err)
+	require.NotEmpty(t, preview)
+	first := preview[0]
+	assert.Equal(t, first.Old, cost(first.Section))
+
+	applied, err := a.ApplyBulkEdit(edit)
+	require.NoError(t, err)
+	assert.Equal(t, preview, applied)
+	assert.Equal(t, first.New, cost(first.Section))
+
+	// 整个批量编辑作为一次修改撤销
+	require.NoError(t, a.Undo())
+	assert.Equal(t, first.Old, cost(first.Section))
+	for _, change := range applied {
+		assert.Equal(t, change.Old, cost(change.Section))
+	}
+
+	_, err = a.ApplyBulkEdit(&ra2.BulkEdit{Query: "type:vehicle", Op: ra2.BulkOpScale, Key: "Armor", Value: "2"})
+	var appErr *AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, ErrorCodeValidation, appErr.Code)
+}
+
+// TestApp_BulkEditShadowed 上层覆盖的 key 按目标层的视图计划，不报告没有效果的修改。
+func TestApp_BulkEditShadowed(t *testing.T) {
+	a := newTestApp(t)
+	target := a.layers.Target()
+	top, err := a.NewLayer("top")
+	require.NoError(t, err)
+	require.NoError(t, a.SetWriteTarget(top.ID))
+	require.NoError(t, a.SaveUnitTable([]TableEdit{{Unit: "HTNK", Key: "Cost", Value: "4321"}}))
+	require.NoError(t, a.SetWriteTarget(target.ID))
+
+	edit := &ra2.BulkEdit{Query: "Cost=4321", Op: ra2.BulkOpSet, Key: "Cost", Value: "1"}
+	preview, err := a.PreviewBulkEdit(edit)
+	require.NoError(t, err)
+	assert.Empty(t, preview)
+
+	edit = &ra2.BulkEdit{Query: "type:vehicle and Owner=Russians", Op: ra2.BulkOpSet, Key: "Cost", Value: "4321"}
+	preview, err = a.PreviewBulkEdit(edit)
+	require.NoError(t, err)
+	htnk, ok := lo.Find(preview, func(c ra2.BulkChange) bool { return c.Section == "HTNK" })
+	require.True(t, ok)
+	assert.NotEqual(t, "4321", htnk.Old)
+}
+
+func TestApp_UnitTable(t *testing.T) {
+	a := newTestApp(t)
+	units := []string{"HTNK", "MTNK"}
+	keys := []string{"Cost", "Speed", "Crusher", "Sensors"}
+
+	table, err := a.GetUnitTable(units, keys)
+	require.NoError(t, err)
+	require.Len(t, table.Columns, len(keys))
+	for _, column := range table.Columns {
+		assert.Len(t, column.Cells, len(units))
+	}
+	cost := table.Columns[0].Cells[0]
+	assert.False(t, cost.Default)
+	assert.NotEmpty(t, cost.Source)
+
+	require.NoError(t, a.SaveUnitTable([]TableEdit{
+		{Unit: "HTNK", Key: "Cost", Value: "1234"},
+		{Unit: "MTNK", Key: "Speed", Value: "9"},
+		// 与下层相同的值不写入目标层
+		{Unit: "HTNK", Key: "Speed", Value: table.Columns[1].Cells[0].Value},
+	}))
+	table, err = a.GetUnitTable(units, keys)
+	require.NoError(t, err)
+	assert.Equal(t, "1234", table.Columns[0].Cells[0].Value)
+	assert.Equal(t, "9", table.Columns[1].Cells[1].Value)
+	assert.NotEqual(t, a.layers.Target(), a.layers.Source("HTNK", "Speed"))
+
+	require.NoError(t, a.Undo())
+	table, err = a.GetUnitTable(units, keys)
+	require.NoError(t, err)
+	assert.Equal(t, cost.Value, table.Columns[0].Cells[0].Value)
+
+	_, err = a.GetUnitTable([]string{"NOPE"}, keys)
+	var appErr *AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, ErrorCodeNotFound, appErr.Code)
+}
+
+// TestApp_UnitTableThenSaveUnit 表格修改在目标层中写出未注册的 section，之后仍可保存该单位。
+func TestApp_UnitTableThenSaveUnit(t *testing.T) {
+	a := newTestApp(t)
+	require.NoError(t, a.SaveUnitTable([]TableEdit{{Unit: "HTNK", Key: "Cost", Value: "1234"}}))
+
+	unit, err := a.GetUnit(string(ra2.UnitTypeVehicle), 4)
+	require.NoError(t, err)
+	for i := range unit.Properties {
+		if unit.Properties[i].Key == "Speed" {
+			unit.Properties[i].Value = "9"
+		}
+	}
+	require.NoError(t, a.SaveUnit(unit))
+
+	r, err := a.getRules()
+	require.NoError(t, err)
+	assert.Equal(t, "1234", r.UnitByName("HTNK").Get("Cost"))
+	assert.Equal(t, "9", r.UnitByName("HTNK").Get("Speed"))
+}
+
+func TestApp_ImportSheet(t *testing.T) {
+	a := newTestApp(t)
+	filename := filepath.Join(t.TempDir(), "units.csv")
+	require.NoError(t, os.WriteFile(filename, []byte("Section,Cost,Speed\nHTNK,1234,\nMTNK,,oops\n"), 0o644))
+
+	_, err := a.ImportSheet(filename)
+	var appErr *AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, ErrorCodeValidation, appErr.Code)
+
+	require.NoError(t, os.WriteFile(filename, []byte("Section,Cost,Speed\nHTNK,1234,\n"), 0o644))
+	changes, err := a.ImportSheet(filename)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "1234", changes[0].New)
+	r, err := a.getRules()
+	require.NoError(t, err)
+	assert.Equal(t, "1234", r.UnitByName("HTNK").Get("Cost"))
+
+	require.NoError(t, a.Undo())
+	r, err = a.getRules()
+	require.NoError(t, err)
+	assert.Equal(t, changes[0].Old, r.UnitByName("HTNK").Get("Cost"))
+}
+
+func TestApp_ImportStructured(t *testing.T) {
+	a := newTestApp(t)
+	filename := filepath.Join(t.TempDir(), "patch.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte("sections:\n  - name: HTNK\n    keys:\n      - {key: Cost, value: 1500}\n"), 0o644))
+
+	a.mu.Lock()
+	err := a.importStructured(filename)
+	a.mu.Unlock()
+	require.NoError(t, err)
+	rules, err := a.UserRules()
+	require.NoError(t, err)
+	assert.Contains(t, rules, "[HTNK]")
+	unit, err := a.GetUnit(string(ra2.UnitTypeVehicle), 4)
+	require.NoError(t, err)
+	cost, _ := lo.Find(unit.Properties, func(p Property) bool { return p.Key == "Cost" })
+	assert.Equal(t, "1500", cost.Value)
+
+	require.NoError(t, a.Undo())
+	rules, err = a.UserRules()
+	require.NoError(t, err)
+	assert.NotContains(t, rules, "[HTNK]")
+}
+
+func TestApp_CombatMatrix(t *testing.T) {
+	a := newTestApp(t)
+
+	m, err := a.CombatMatrix([]string{"HTNK", "MTNK"}, []string{"MTNK"}, "rookie", "veteran")
+	require.NoError(t, err)
+	require.Len(t, m.Cells, 2)
+	assert.Equal(t, "Primary", m.Cells[0][0].Slot)
+	assert.Greater(t, m.Cells[0][0].TTK, 0.0)
+
+	// 精英单位的伤害更高
+	elite, err := a.CombatMatrix([]string{"HTNK"}, []string{"MTNK"}, "elite", "veteran")
+	require.NoError(t, err)
+	assert.Greater(t, elite.Cells[0][0].DPS, m.Cells[0][0].DPS)
+
+	var appErr *AppError
+	_, err = a.CombatMatrix([]string{"NOPE"}, []string{"MTNK"}, "rookie", "rookie")
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, ErrorCodeNotFound, appErr.Code)
+	_, err = a.CombatMatrix([]string{"HTNK"}, []string{"MTNK"}, "heroic", "rookie")
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, ErrorCodeValidation, appErr.Code)
+}
+
+func TestApp_Economy(t *testing.T) {
+	a := newTestApp(t)
+
+	report, err := a.Economy([]string{"HTNK", "NAPOWR", "NAWEAP"}, ra2.EconomyOptions{Armor: "heavy"})
+	require.NoError(t, err)
+	require.Len(t, report.Rows, 3)
+	assert.Greater(t, report.Rows[0].BuildTime, 0.0)
+	assert.Greater(t, report.Rows[0].CostPerDPS, 0.0)
+	assert.Equal(t, report.Power.Output-report.Power.Drain, report.Power.Balance)
+	assert.Greater(t, report.Power.Drain, 0.0)
+
+	// 工厂越多建造越快
+	faster, err := a.Economy([]string{"HTNK"}, ra2.EconomyOptions{Factories: 3})
+	require.NoError(t, err)
+	assert.Less(t, faster.Rows[0].BuildTime, report.Rows[0].BuildTime)
+
+	all, err := a.Economy(nil, ra2.EconomyOptions{})
+	require.NoError(t, err)
+	assert.Greater(t, len(all.Rows), len(report.Rows))
+
+	var appErr *AppError
+	_, err = a.Economy(nil, ra2.EconomyOptions{Armor: "paper"})
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, ErrorCodeValidation, appErr.Code)
+}
+
+func TestApp_Lint(t *testing.T) {
+	a := newTestApp(t)
+
+	// 没有修改时覆盖层中没有问题
+	issues, err := a.Lint(false)
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+	all, err := a.Lint(true)
+	require.NoError(t, err)
+	assert.NotEmpty(t, all)
+
+	require.NoError(t, a.SaveUnitTable([]TableEdit{{Unit: "HTNK", Key: "Strenght", Value: "500"}}))
+	issues, err = a.Lint(false)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "unknown-key", issues[0].Rule)
+	assert.Equal(t, []string{"Strength"}, issues[0].Suggestions)
+
+	require.NoError(t, a.SetLintRule("unknown-key", true, "error"))
+	issues, err = a.Lint(false)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, lint.SeverityError, issues[0].Severity)
+
+	require.NoError(t, a.SetLintRule("unknown-key", false, ""))
+	issues, err = a.Lint(false)
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+	rules, err := a.ListLintRules()
+	require.NoError(t, err)
+	assert.True(t, lo.ContainsBy(rules, func(r LintRule) bool { return r.ID == "unknown-key" && r.Disabled }))
+
+	var appErr *AppError
+	err = a.SetLintRule("no-such-rule", true, "")
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, ErrorCodeNotFound, appErr.Code)
+}
+
+func TestApp_UnitSuggestions(t *testing.T) {
+	a := newTestApp(t)
+	require.NoError(t, a.SaveUnitTable([]TableEdit{{Unit: "HTNK", Key: "Strenght", Value: "500"}}))
+	unit, err := a.GetUnit(string(ra2.UnitTypeVehicle), 4)
+	require.NoError(t, err)
+	prop, ok := lo.Find(unit.Properties, func(p Property) bool { return p.Key == "Strenght" })
+	require.True(t, ok)
+	assert.Equal(t, []string{"Strength"}, prop.Suggestions)
+	strength, _ := lo.Find(unit.Properties, func(p Property) bool { return p.Key == "Strength" })
+	assert.Empty(t, strength.Suggestions)
+
+	suggestions, err := a.SuggestUnitKeys(string(ra2.UnitTypeVehicle), "strenght")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Strength"}, suggestions)
+	suggestions, err = a.SuggestUnitKeys(string(ra2.UnitTypeVehicle), "Strength")
+	require.NoError(t, err)
+	assert.Empty(t, suggestions)
+
+	_, err = a.SuggestUnitKeys("tank", "Strength")
+	var appErr *AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, ErrorCodeValidation, appErr.Code)
+}
+
+func TestApp_OpenUndoSave(t *testing.T) {
+	a := newTestApp(t)
+	dir := t.TempDir()
+	before := filepath.Join(dir, "before.ini")
+	opened := filepath.Join(dir, "opened.ini")
+	require.NoError(t, os.WriteFile(opened, []byte("[HTNK]\nCost=1500\n"), 0o644))
+	require.NoError(t, a.SaveUnitTable([]TableEdit{{Unit: "HTNK", Key: "Cost", Value: "1234"}}))
+
+	a.mu.Lock()
+	target := a.layers.Target()
+	require.NoError(t, a.saveLayer(target, before))
+	name := target.Name
+	err := a.openFile(opened)
+	a.mu.Unlock()
+	require.NoError(t, err)
+	assert.Equal(t, opened, target.Path)
+	assert.False(t, target.Dirty)
+
+	// 撤销打开后保存写回原来的文件，不覆盖刚打开的文件
+	require.NoError(t, a.Undo())
+	assert.Equal(t, before, target.Path)
+	assert.Equal(t, name, target.Name)
+	require.NoError(t, a.Save())
+	content, err := os.ReadFile(opened)
+	require.NoError(t, err)
+	assert.Equal(t, "[HTNK]\nCost=1500\n", string(content))
+	content, err = os.ReadFile(before)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Cost = 1234")
+
+	require.NoError(t, a.Redo())
+	assert.Equal(t, opened, target.Path)
+	r, err := a.getRules()
+	require.NoError(t, err)
+	assert.Equal(t, "1500", r.UnitByName("HTNK").Get("Cost"))
+}
+
+func TestApp_OpenMapUndo(t *testing.T) {
+	a := newTestApp(t)
+	filename := filepath.Join(t.TempDir(), "test.map")
+	require.NoError(t, os.WriteFile(filename, []byte("[Basic]\nName=Test Map\n[HTNK]\nCost=1500\n"), 0o644))
+
+	a.mu.Lock()
+	target := a.layers.Target()
+	err := a.openFile(filename)
+	a.mu.Unlock()
+	require.NoError(t, err)
+	assert.NotNil(t, target.Map)
+
+	// 撤销后不再关联地图，保存时不会把规则写入地图
+	require.NoError(t, a.Undo())
+	assert.Nil(t, target.Map)
+	assert.Empty(t, target.Path)
+	require.NoError(t, a.Redo())
+	assert.NotNil(t, target.Map)
+	assert.Equal(t, filename, target.Path)
+}
+
+func TestApp_SaveMapUnit(t *testing.T) {
+	a := newTestApp(t)
+	filename := filepath.Join(t.TempDir(), "test.map")
+	require.NoError(t, os.WriteFile(filename, []byte("[Basic]\nName=Test Map\n[HTNK]\nCost=1500\n"), 0o644))
+	a.mu.Lock()
+	err := a.openFile(filename)
+	a.mu.Unlock()
+	require.NoError(t, err)
+
+	unit, err := a.GetUnit(string(ra2.UnitTypeVehicle), 4)
+	require.NoError(t, err)
+	for i := range unit.Properties {
+		if unit.Properties[i].Key == "Cost" {
+			unit.Properties[i].Value = "1600"
+		}
+	}
+	require.NoError(t, a.SaveUnit(unit))
+
+	// 地图中的单位覆盖原地修改，不在地图中注册
+	require.NoError(t, a.Save())
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Cost=1600")
+	assert.NotContains(t, string(content), "[VehicleTypes]")
+}
+
+// TestApp_RecoverDocuments 只修改了 AI 文件时崩溃，自动保存的内容同样可以恢复。
+func TestApp_RecoverDocuments(t *testing.T) {
+	a := newTestApp(t)
+	tf, err := a.SaveTaskForce(&ra2.TaskForce{Name: "Rhinos", Members: []ra2.TaskForceMember{{Count: 5, Type: "HTNK"}}})
+	require.NoError(t, err)
+	require.NoError(t, a.autosave())
+
+	// 模拟崩溃后重新启动
+	b, err := NewApp()
+	require.NoError(t, err)
+	rec, err := b.GetRecovery()
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, []string{a.ai.Name}, rec.Layers)
+
+	require.NoError(t, b.RestoreRecovery())
+	taskForces, err := b.ListTaskForces()
+	require.NoError(t, err)
+	got, ok := lo.Find(taskForces, func(f *ra2.TaskForce) bool { return f.ID == tf.ID })
+	require.True(t, ok)
+	assert.Equal(t, "Rhinos", got.Name)
+	unsaved, err := b.HasUnsavedChanges()
+	require.NoError(t, err)
+	assert.True(t, unsaved)
+}
+
+func TestApp_LayerStructure(t *testing.T) {
+	a := newTestApp(t)
+	base := a.baseLayer()
+	require.NotNil(t, base)
+
+	revision := a.revision
+	layer, err := a.NewLayer("patch")
+	require.NoError(t, err)
+	assert.Greater(t, a.revision, revision)
+
+	// 基础层固定在底部
+	var appErr *AppError
+	require.ErrorAs(t, a.MoveLayer(layer.ID, 0), &appErr)
+	assert.Equal(t, ErrorCodeValidation, appErr.Code)
+	require.ErrorAs(t, a.MoveLayer(base.ID, 1), &appErr)
+	assert.Same(t, base, a.baseLayer())
+
+	revision = a.revision
+	require.NoError(t, a.SetLayerEnabled(layer.ID, false))
+	assert.Greater(t, a.revision, revision)
+	revision = a.revision
+	require.NoError(t, a.RemoveLayer(layer.ID))
+	assert.Greater(t, a.revision, revision)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"ra2-ini-editor/internal/ra2"
+)
+
+func runCombat(args []string) error {
+	fs := flag.NewFlagSet("combat", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: ra2ini combat [flags] <attackers> <defenders> [layer.ini ...]")
+		fmt.Fprintln(fs.Output(), "example: ra2ini combat -attacker elite HTNK,APOC MTNK,SREF")
+		fmt.Fprintln(fs.Output(), "输出每个攻击方击杀防守方的秒数，括号中为 DPS，- 表示无法造成伤害")
+		fs.PrintDefaults()
+	}
+	var wf workspaceFlags
+	wf.register(fs)
+	attackerLevel := fs.String("attacker", "rookie", "攻击方等级：rookie、veteran 或 elite")
+	defenderLevel := fs.String("defender", "rookie", "防守方等级：rookie、veteran 或 elite")
+	asJSON := fs.Bool("json", false, "以 JSON 输出")
+	fs.Parse(args)
+	if fs.NArg() < 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	attackerVet, ok := ra2.NewVeterancy(*attackerLevel)
+	if !ok {
+		return fmt.Errorf("unknown veterancy %q", *attackerLevel)
+	}
+	defenderVet, ok := ra2.NewVeterancy(*defenderLevel)
+	if !ok {
+		return fmt.Errorf("unknown veterancy %q", *defenderLevel)
+	}
+	ws, err := wf.load(fs.Args()[2:])
+	if err != nil {
+		return err
+	}
+	rules, err := ws.Layers.Merged()
+	if err != nil {
+		return err
+	}
+	attackers, err := findUnits(rules, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defenders, err := findUnits(rules, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	m := rules.Combat(attackers, defenders, attackerVet, defenderVet)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(m)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\t"+strings.Join(m.Defenders, "\t"))
+	for i, attacker := range m.Attackers {
+		fields := []string{attacker}
+		for _, cell := range m.Cells[i] {
+			switch {
+			case cell.Weapon == "":
+				fields = append(fields, "-")
+			case cell.TTK < 0:
+				fields = append(fields, fmt.Sprintf("- (%.1f)", cell.DPS))
+			default:
+				fields = append(fields, fmt.Sprintf("%.1fs (%.1f)", cell.TTK, cell.DPS))
+			}
+		}
+		fmt.Fprintln(w, strings.Join(fields, "\t"))
+	}
+	return w.Flush()
+}
+
+// findUnits 按逗号分隔的注册名查找单位。
+func findUnits(rules *ra2.Rules, names string) ([]*ra2.Unit, error) {
+	var units []*ra2.Unit
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		unit := rules.UnitByName(name)
+		if unit == nil {
+			return nil, fmt.Errorf("unit %s not found", name)
+		}
+		units = append(units, unit)
+	}
+	return units, nil
+}
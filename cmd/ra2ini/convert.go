@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ra2-ini-editor/internal/fileutil"
+	"ra2-ini-editor/internal/ra2"
+	"ra2-ini-editor/internal/workspace"
+)
+
+func runConvert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: ra2ini convert <input> <output>")
+		fmt.Fprintln(fs.Output(), "按扩展名在 INI、JSON 与 YAML 之间转换规则文件")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	input, output := fs.Arg(0), fs.Arg(1)
+
+	var rules *ra2.Rules
+	if format, ok := ra2.DocumentFormatOf(input); ok {
+		f, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		doc, err := ra2.ReadDocument(f, format)
+		if err != nil {
+			return err
+		}
+		if rules, err = ra2.NewRulesFromDocument(doc); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if rules, err = workspace.LoadRules(input); err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	if format, ok := ra2.DocumentFormatOf(output); ok {
+		if err := ra2.WriteDocument(&buf, format, rules.Document()); err != nil {
+			return err
+		}
+	} else if strings.EqualFold(filepath.Ext(output), ".ini") {
+		if err := rules.Save(&buf); err != nil {
+			return err
+		}
+	} else {
+		return fmt.Errorf("unsupported output format %s", filepath.Ext(output))
+	}
+	return fileutil.WriteFileAtomic(output, buf.Bytes(), true)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"ra2-ini-editor/internal/docs"
+	"ra2-ini-editor/internal/ra2"
+)
+
+func runDocs(args []string) error {
+	fs := flag.NewFlagSet("docs", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: ra2ini docs [flags] [layer.ini ...]")
+		fmt.Fprintln(fs.Output(), `example: ra2ini docs -format html -o site -query "type:vehicle" mod.ini`)
+		fs.PrintDefaults()
+	}
+	var wf workspaceFlags
+	wf.register(fs)
+	formatName := fs.String("format", "md", "输出格式：md 或 html")
+	output := fs.String("o", "docs", "输出目录")
+	expr := fs.String("query", "", "只为匹配的单位生成资料页")
+	fs.Parse(args)
+
+	format, ok := docs.ParseFormat(*formatName)
+	if !ok {
+		return fmt.Errorf("unknown format %q, expected md or html", *formatName)
+	}
+	var q *ra2.Query
+	if *expr != "" {
+		var err error
+		if q, err = ra2.ParseQuery(*expr); err != nil {
+			return err
+		}
+	}
+	ws, err := wf.load(fs.Args())
+	if err != nil {
+		return err
+	}
+	rules, err := ws.Layers.Merged()
+	if err != nil {
+		return err
+	}
+
+	units := rules.Units()
+	if q != nil {
+		units = nil
+		for _, r := range rules.Query(q, ws.Schema) {
+			if unit := rules.UnitByName(r.Section); unit != nil {
+				units = append(units, unit)
+			}
+		}
+	}
+	g := &docs.Generator{Rules: rules, Translation: ws.Translation, Schema: ws.Schema}
+	pages := g.Pages(units)
+	if err := docs.Write(*output, format, pages); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "wrote %d pages to %s\n", len(pages), *output)
+	return nil
+}
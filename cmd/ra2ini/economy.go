@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"ra2-ini-editor/internal/fileutil"
+	"ra2-ini-editor/internal/ra2"
+	"ra2-ini-editor/internal/sheet"
+)
+
+func runEconomy(args []string) error {
+	fs := flag.NewFlagSet("economy", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: ra2ini economy [flags] [layer.ini ...]")
+		fmt.Fprintln(fs.Output(), `example: ra2ini economy -query "type:vehicle and Owner=Russians" -factories 2 -o economy.xlsx`)
+		fs.PrintDefaults()
+	}
+	var wf workspaceFlags
+	wf.register(fs)
+	unitNames := fs.String("units", "", "逗号分隔的单位注册名，为空时计算所有单位")
+	expr := fs.String("query", "", "只计算匹配的单位")
+	var opts ra2.EconomyOptions
+	fs.IntVar(&opts.Factories, "factories", 1, "同类工厂的数量")
+	fs.StringVar(&opts.Armor, "armor", "none", "计算 DPS 时目标的护甲类型")
+	output := fs.String("o", "", "导出为 CSV 或 XLSX 文件，为空时输出到标准输出")
+	fs.Parse(args)
+
+	if !ra2.IsArmorType(opts.Armor) {
+		return fmt.Errorf("unknown armor %q, expected one of %s", opts.Armor, strings.Join(ra2.ArmorTypes, ", "))
+	}
+	var q *ra2.Query
+	if *expr != "" {
+		var err error
+		if q, err = ra2.ParseQuery(*expr); err != nil {
+			return err
+		}
+	}
+	ws, err := wf.load(fs.Args())
+	if err != nil {
+		return err
+	}
+	rules, err := ws.Layers.Merged()
+	if err != nil {
+		return err
+	}
+
+	units := rules.Units()
+	switch {
+	case *unitNames != "":
+		if units, err = findUnits(rules, *unitNames); err != nil {
+			return err
+		}
+	case q != nil:
+		units = nil
+		for _, r := range rules.Query(q, ws.Schema) {
+			if unit := rules.UnitByName(r.Section); unit != nil {
+				units = append(units, unit)
+			}
+		}
+	}
+	rows := rules.Economy(units, opts)
+
+	if *output != "" {
+		format, ok := sheet.FormatOf(*output)
+		if !ok {
+			return fmt.Errorf("unsupported sheet format %s", filepath.Ext(*output))
+		}
+		var buf bytes.Buffer
+		if err := sheet.Write(&buf, format, sheet.EconomyTable(rows)); err != nil {
+			return err
+		}
+		return fileutil.WriteFileAtomic(*output, buf.Bytes(), true)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Name\t"+strings.Join(ra2.EconomyColumns, "\t"))
+	var buildings []*ra2.Unit
+	for i, row := range rows {
+		fmt.Fprintln(w, row.Name+"\t"+strings.Join(row.Values(), "\t"))
+		if units[i].Type == ra2.UnitTypeBuilding {
+			buildings = append(buildings, units[i])
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if len(buildings) > 0 {
+		power := rules.PowerBalance(buildings)
+		fmt.Printf("power: output %g, drain %g, balance %g\n", power.Output, power.Drain, power.Balance)
+	}
+	return nil
+}
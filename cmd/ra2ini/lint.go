@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"ra2-ini-editor/internal/lint"
+	"ra2-ini-editor/internal/project"
+	"ra2-ini-editor/internal/ra2"
+	"ra2-ini-editor/internal/workspace"
+)
+
+func runLint(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: ra2ini lint [flags] [layer.ini ...]")
+		fmt.Fprintln(fs.Output(), "检查覆盖层中的问题，有不低于 -fail-on 级别的问题时以非零状态退出")
+		fs.PrintDefaults()
+	}
+	var wf workspaceFlags
+	wf.register(fs)
+	all := fs.Bool("all", false, "同时报告基础规则中的问题")
+	asJSON := fs.Bool("json", false, "以 JSON 输出")
+	failOn := fs.String("fail-on", "warning", "导致失败的最低级别：error、warning 或 info")
+	listRules := fs.Bool("rules", false, "列出所有规则后退出")
+	fs.Parse(args)
+
+	rules := lint.DefaultRules()
+	if *listRules {
+		for _, rule := range rules {
+			fmt.Printf("%-22s %-8s %s\n", rule.ID, rule.Severity, rule.Desc)
+		}
+		return nil
+	}
+	threshold, ok := lint.ParseSeverity(*failOn)
+	if !ok {
+		return fmt.Errorf("unknown severity %q", *failOn)
+	}
+	ws, err := wf.load(fs.Args())
+	if err != nil {
+		return err
+	}
+	merged, err := ws.Layers.Merged()
+	if err != nil {
+		return err
+	}
+	ctx := &lint.Context{
+		Rules:       merged,
+		Schema:      ws.Schema,
+		Translation: ws.Translation,
+		AI:          ra2.NewAI(ws.AI.Rules),
+		Sounds:      ra2.NewSoundList(ws.Sound.Rules),
+		EVA:         ra2.NewEVAList(ws.EVA.Rules),
+		Engine:      ws.Project.Engine,
+	}
+	if ctx.Engine == "" {
+		if ctx.EngineSchema, err = workspace.LoadEngineSchema(project.EngineAres); err != nil {
+			return err
+		}
+	}
+	opts, err := lint.ProjectOptions(ws.Project.Lint)
+	if err != nil {
+		return err
+	}
+	if !*all {
+		opts.Scope = lint.LayerScope(ws.Layers)
+	}
+	issues, err := lint.Run(ctx, rules, opts)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if issues == nil {
+			issues = []lint.Issue{}
+		}
+		if err := enc.Encode(issues); err != nil {
+			return err
+		}
+	} else {
+		for _, issue := range issues {
+			location := "[" + issue.Section + "]"
+			if issue.Key != "" {
+				location += " " + issue.Key
+			}
+			fmt.Printf("%s: %s: %s (%s)\n", issue.Severity, location, issue.Message, issue.Rule)
+		}
+	}
+
+	failed := 0
+	for _, issue := range issues {
+		if issue.Severity.AtLeast(threshold) {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d %s at or above %s", failed, plural(failed, "issue"), threshold)
+	}
+	return nil
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
@@ -0,0 +1,98 @@
+// ra2ini 是 ra2-ini-editor 的命令行工具，用于在脚本或 CI 中处理 mod 项目。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ra2-ini-editor/internal/project"
+	"ra2-ini-editor/internal/workspace"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{name: "package", usage: "将规则打包为 MIX 文件", run: runPackage},
+	{name: "query", usage: "在合并后的规则中查找 section", run: runQuery},
+	{name: "convert", usage: "在 INI、JSON 与 YAML 之间转换规则文件", run: runConvert},
+	{name: "docs", usage: "生成 Markdown 或 HTML 格式的单位资料页", run: runDocs},
+	{name: "combat", usage: "计算单位之间的 DPS 与击杀时间", run: runCombat},
+	{name: "economy", usage: "计算建造时间、性价比与电力", run: runEconomy},
+	{name: "lint", usage: "检查规则中的常见问题，可用于 CI", run: runLint},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, cmd := range commands {
+		if cmd.name == os.Args[1] {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", cmd.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: ra2ini <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.usage)
+	}
+}
+
+// workspaceFlags 是各命令共用的加载参数。
+type workspaceFlags struct {
+	project string
+	gameDir string
+}
+
+func (f *workspaceFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.project, "project", "", "项目文件，为空时使用内置数据")
+	fs.StringVar(&f.gameDir, "game-dir", "", "游戏目录，覆盖项目中的设置")
+}
+
+// load 加载项目，layers 中的 INI 文件依次作为覆盖层压入栈顶。
+func (f *workspaceFlags) load(layers []string) (*workspace.Workspace, error) {
+	p := project.Default()
+	p.Layers = nil
+	if f.project != "" {
+		loaded, err := project.Load(f.project)
+		if err != nil {
+			return nil, err
+		}
+		p = loaded
+	}
+	if f.gameDir != "" {
+		abs, err := filepath.Abs(f.gameDir)
+		if err != nil {
+			return nil, err
+		}
+		p.GameDir = abs
+	}
+	for _, layer := range layers {
+		abs, err := filepath.Abs(layer)
+		if err != nil {
+			return nil, err
+		}
+		p.Layers = append(p.Layers, project.Layer{
+			Name:    strings.TrimSuffix(filepath.Base(layer), filepath.Ext(layer)),
+			Path:    abs,
+			Enabled: true,
+		})
+	}
+	return workspace.Load(p)
+}
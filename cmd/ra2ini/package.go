@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+
+	"ra2-ini-editor/internal/fileutil"
+	"ra2-ini-editor/internal/workspace"
+)
+
+func runPackage(args []string) error {
+	fs := flag.NewFlagSet("package", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: ra2ini package [flags] [layer.ini ...]")
+		fs.PrintDefaults()
+	}
+	var wf workspaceFlags
+	wf.register(fs)
+	output := fs.String("o", "", "输出文件，默认使用项目中的设置或 "+workspace.DefaultPackageName)
+	fs.Parse(args)
+
+	ws, err := wf.load(fs.Args())
+	if err != nil {
+		return err
+	}
+	filename := *output
+	if filename == "" {
+		filename = ws.Project.Resolve(ws.Project.Package.Output)
+	}
+	if filename == "" {
+		filename = workspace.DefaultPackageName
+	}
+
+	var buf bytes.Buffer
+	if err := ws.Package(&buf); err != nil {
+		return err
+	}
+	if err := fileutil.WriteFileAtomic(filename, buf.Bytes(), true); err != nil {
+		return err
+	}
+	fmt.Printf("packaged %s\n", filename)
+	return nil
+}
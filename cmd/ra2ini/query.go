@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"ra2-ini-editor/internal/ra2"
+)
+
+func runQuery(args []string) error {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: ra2ini query [flags] <expr> [layer.ini ...]")
+		fmt.Fprintln(fs.Output(), `example: ra2ini query "type:vehicle and Armor=heavy and Speed>6 and Owner=Russians"`)
+		fs.PrintDefaults()
+	}
+	var wf workspaceFlags
+	wf.register(fs)
+	asJSON := fs.Bool("json", false, "以 JSON 输出")
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	q, err := ra2.ParseQuery(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	ws, err := wf.load(fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+	rules, err := ws.Layers.Merged()
+	if err != nil {
+		return err
+	}
+	results := rules.Query(q, ws.Schema)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if results == nil {
+			results = []ra2.QueryResult{}
+		}
+		return enc.Encode(results)
+	}
+	for _, r := range results {
+		fields := []string{r.Section}
+		for _, key := range q.Keys() {
+			if v, ok := r.Values[key]; ok {
+				fields = append(fields, key+"="+v)
+			}
+		}
+		fmt.Println(strings.Join(fields, "\t"))
+	}
+	return nil
+}
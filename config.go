@@ -0,0 +1,19 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// getConfigDir 返回应用的配置目录，日志、自动保存等文件都保存在这里。
+func getConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "ra2-ini-editor")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
@@ -0,0 +1,7 @@
+// Package data 内置了原版尤里的复仇的数据文件，没有指定游戏目录时使用。
+package data
+
+import "embed"
+
+//go:embed schema rulesmd.ini artmd.ini ra2md.ini ra2md.csf
+var FS embed.FS
@@ -1,11 +1,21 @@
-import { Button, ConfigProvider, Layout, message, Typography } from "antd";
+import {
+  Button,
+  ConfigProvider,
+  Layout,
+  message,
+  Modal,
+  Typography,
+} from "antd";
 import { useEffect, useState } from "react";
 import {
   DeleteUnit,
+  DiscardRecovery,
+  GetRecovery,
   GetUnit,
   ListAllUnits,
   NextUnitID,
   Open,
+  RestoreRecovery,
   Save,
   SaveUnit,
   UserRules,
@@ -33,6 +43,34 @@ function App() {
       });
   }, []);
 
+  useEffect(() => {
+    GetRecovery()
+      .then((recovery) => {
+        if (!recovery) return;
+        Modal.confirm({
+          title: "恢复未保存的修改",
+          content: `上次退出时 ${recovery.layers.join(", ")} 有未保存的修改，是否恢复？`,
+          okText: "恢复",
+          cancelText: "丢弃",
+          onOk: () =>
+            RestoreRecovery()
+              .then(() => ListAllUnits())
+              .then((units) => setUnits(units))
+              .catch((err) => {
+                console.error("Error restoring recovery:", err);
+              }),
+          onCancel: () => {
+            DiscardRecovery().catch((err) => {
+              console.error("Error discarding recovery:", err);
+            });
+          },
+        });
+      })
+      .catch((err) => {
+        console.error("Error getting recovery:", err);
+      });
+  }, []);
+
   const handleAddUnit = (type: string, name: string) => {
     NextUnitID(type)
       .then((id) => {
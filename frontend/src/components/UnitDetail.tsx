@@ -1,7 +1,19 @@
 import { PlusOutlined } from "@ant-design/icons";
-import { AutoComplete, Button, Input, List, Tooltip, Typography } from "antd";
+import {
+  AutoComplete,
+  Button,
+  Input,
+  List,
+  Select,
+  Tooltip,
+  Typography,
+} from "antd";
 import { useEffect, useState } from "react";
-import { ListAvailableProperties, NewULID } from "../../wailsjs/go/main/App";
+import {
+  ListAvailableProperties,
+  NewULID,
+  SuggestUnitKeys,
+} from "../../wailsjs/go/main/App";
 import { main } from "../../wailsjs/go/models";
 
 interface UnitDetailProps {
@@ -48,12 +60,52 @@ const UnitDetail: React.FC<UnitDetailProps> = ({ value, onChange }) => {
     updateUnitProperties(newProperties);
   };
 
+  // 建议只用于提示，不触发 onChange
+  const setPropertySuggestions = (ukey: string, suggestions: string[]) => {
+    setCurrentUnit((prev) =>
+      main.Unit.createFrom({
+        ...prev,
+        properties: prev.properties.map((p) =>
+          p.ukey === ukey ? { ...p, suggestions } : p
+        ),
+      })
+    );
+  };
+
+  const handleKeyChange = (index: number, key: string) => {
+    const ukey = currentUnit.properties[index].ukey;
+    handlePropertyChange(index, "key", key);
+    if (!key) {
+      setPropertySuggestions(ukey, []);
+      return;
+    }
+    SuggestUnitKeys(currentUnit.type, key)
+      .then((suggestions) => setPropertySuggestions(ukey, suggestions))
+      .catch((error) => {
+        console.error("Failed to suggest keys:", error);
+      });
+  };
+
+  const handleApplySuggestion = (index: number, key: string) => {
+    const newProperties = [...currentUnit.properties];
+    newProperties[index] = { ...newProperties[index], key, suggestions: [] };
+    updateUnitProperties(newProperties);
+  };
+
   const handleAddProperty = () => {
     NewULID()
       .then((ukey) => {
         const newProperties = [
           ...currentUnit.properties,
-          { ukey: ukey, key: "", value: "", comment: "" },
+          {
+            ukey: ukey,
+            key: "",
+            value: "",
+            comment: "",
+            action: "",
+            source_id: "",
+            source: "",
+          },
         ];
         updateUnitProperties(newProperties);
       })
@@ -64,7 +116,22 @@ const UnitDetail: React.FC<UnitDetailProps> = ({ value, onChange }) => {
 
   const handleDeleteProperty = (index: number) => {
     const newProperties = [...currentUnit.properties];
-    newProperties.splice(index, 1);
+    if (newProperties[index].origin != null) {
+      // 下层已有的属性无法真正删除，默认写入空值
+      newProperties[index] = { ...newProperties[index], action: "clear" };
+    } else {
+      newProperties.splice(index, 1);
+    }
+    updateUnitProperties(newProperties);
+  };
+
+  const handleUndoDeleteProperty = (index: number) => {
+    const newProperties = [...currentUnit.properties];
+    newProperties[index] = {
+      ...newProperties[index],
+      action: "",
+      value: newProperties[index].origin ?? "",
+    };
     updateUnitProperties(newProperties);
   };
 
@@ -112,51 +179,94 @@ const UnitDetail: React.FC<UnitDetailProps> = ({ value, onChange }) => {
         renderItem={(item, index) => (
           <List.Item
             actions={[
-              <Button
-                type="text"
-                danger
-                onClick={() => handleDeleteProperty(index)}
-              >
-                删除
-              </Button>,
+              item.action ? (
+                <Button
+                  type="text"
+                  onClick={() => handleUndoDeleteProperty(index)}
+                >
+                  撤销
+                </Button>
+              ) : (
+                <Button
+                  type="text"
+                  danger
+                  onClick={() => handleDeleteProperty(index)}
+                >
+                  删除
+                </Button>
+              ),
             ]}
           >
             <div style={{ display: "flex", gap: 16, flex: 1 }}>
-              <AutoComplete
-                value={item.key}
-                options={availableProperties.map((prop) => ({
-                  value: prop.key,
-                  label: (
-                    <Tooltip
-                      title={prop.desc}
-                      placement="right"
-                      style={{ width: "300px" }}
-                    >
-                      <div>{prop.key}</div>
-                    </Tooltip>
-                  ),
-                }))}
-                style={{ width: 200 }}
-                filterOption={(inputValue, option) => {
-                  if (!option) return false;
-                  const optionValue = option.value.toLowerCase();
-                  return optionValue.includes(inputValue.toLowerCase());
-                }}
-                onSelect={(value) => {
-                  handlePropertyChange(index, "key", value);
-                }}
-                onChange={(value) => {
-                  handlePropertyChange(index, "key", value);
-                }}
-              />
-              <Input
-                value={item.value}
-                style={{ width: 200 }}
-                placeholder="值"
-                onChange={(e) =>
-                  handlePropertyChange(index, "value", e.target.value)
-                }
-              />
+              <div style={{ display: "flex", flexDirection: "column", width: 200 }}>
+                <AutoComplete
+                  value={item.key}
+                  options={availableProperties.map((prop) => ({
+                    value: prop.key,
+                    label: (
+                      <Tooltip
+                        title={prop.desc}
+                        placement="right"
+                        style={{ width: "300px" }}
+                      >
+                        <div>{prop.key}</div>
+                      </Tooltip>
+                    ),
+                  }))}
+                  style={{ width: 200 }}
+                  status={item.suggestions?.length ? "warning" : undefined}
+                  filterOption={(inputValue, option) => {
+                    if (!option) return false;
+                    const optionValue = option.value.toLowerCase();
+                    return optionValue.includes(inputValue.toLowerCase());
+                  }}
+                  onSelect={(value) => {
+                    handleKeyChange(index, value);
+                  }}
+                  onChange={(value) => {
+                    handleKeyChange(index, value);
+                  }}
+                />
+                {item.suggestions && item.suggestions.length > 0 && (
+                  <Typography.Text type="warning" style={{ fontSize: 12 }}>
+                    未知属性，是否为
+                    {item.suggestions.map((suggestion) => (
+                      <Button
+                        key={suggestion}
+                        type="link"
+                        size="small"
+                        style={{ padding: "0 4px", height: "auto" }}
+                        onClick={() => handleApplySuggestion(index, suggestion)}
+                      >
+                        {suggestion}
+                      </Button>
+                    ))}
+                  </Typography.Text>
+                )}
+              </div>
+              {item.action ? (
+                <Select
+                  value={item.action}
+                  style={{ width: 200 }}
+                  options={[
+                    { value: "clear", label: "清空" },
+                    { value: "reset", label: "恢复默认值" },
+                    { value: "inherit", label: "沿用原值" },
+                  ]}
+                  onChange={(value) =>
+                    handlePropertyChange(index, "action", value)
+                  }
+                />
+              ) : (
+                <Input
+                  value={item.value}
+                  style={{ width: 200 }}
+                  placeholder="值"
+                  onChange={(e) =>
+                    handlePropertyChange(index, "value", e.target.value)
+                  }
+                />
+              )}
               <Input
                 value={item.comment}
                 style={{ flex: 1 }}
@@ -1,23 +1,165 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {ra2} from '../models';
 import {main} from '../models';
+import {lint} from '../models';
+
+export function AddLayer():Promise<void>;
+
+export function ApplyBulkEdit(arg1:ra2.BulkEdit):Promise<Array<ra2.BulkChange>>;
+
+export function ClearGameDir():Promise<void>;
+
+export function CombatMatrix(arg1:Array<string>,arg2:Array<string>,arg3:string,arg4:string):Promise<ra2.CombatMatrix>;
+
+export function DeleteAITriggerType(arg1:string):Promise<void>;
+
+export function DeleteEVAEvent(arg1:string):Promise<void>;
+
+export function DeleteScriptType(arg1:string):Promise<void>;
+
+export function DeleteSoundEntry(arg1:string):Promise<void>;
+
+export function DeleteTaskForce(arg1:string):Promise<void>;
+
+export function DeleteTeamType(arg1:string):Promise<void>;
 
 export function DeleteUnit(arg1:string,arg2:number):Promise<void>;
 
+export function DiscardRecovery():Promise<void>;
+
+export function Economy(arg1:Array<string>,arg2:ra2.EconomyOptions):Promise<main.EconomyReport>;
+
+export function ExportEconomy(arg1:Array<string>,arg2:ra2.EconomyOptions):Promise<void>;
+
+export function ExportSheet(arg1:Array<string>,arg2:Array<string>):Promise<void>;
+
+export function ExportStructured(arg1:boolean):Promise<void>;
+
+export function GetAIFile():Promise<main.Document>;
+
+export function GetEVAFile():Promise<main.Document>;
+
+export function GetGameFiles():Promise<main.GameFiles>;
+
+export function GetRecovery():Promise<main.Recovery>;
+
+export function GetSoundFile():Promise<main.Document>;
+
 export function GetUnit(arg1:string,arg2:number):Promise<main.Unit>;
 
+export function GetUnitTable(arg1:Array<string>,arg2:Array<string>):Promise<main.UnitTable>;
+
+export function HasUnsavedChanges():Promise<boolean>;
+
+export function History():Promise<Array<main.HistoryEntry>>;
+
+export function ImportSheet(arg1:string):Promise<Array<ra2.BulkChange>>;
+
+export function ImportStructured():Promise<void>;
+
+export function Lint(arg1:boolean):Promise<Array<lint.Issue>>;
+
+export function ListAITriggerTypes():Promise<Array<ra2.AITriggerType>>;
+
 export function ListAllUnits():Promise<Array<main.Unit>>;
 
 export function ListAvailableProperties(arg1:string):Promise<Array<main.Property>>;
 
+export function ListEVAEvents():Promise<Array<ra2.SoundEntry>>;
+
+export function ListLayers():Promise<Array<main.Layer>>;
+
+export function ListLintRules():Promise<Array<main.LintRule>>;
+
+export function ListRecentFiles():Promise<Array<string>>;
+
+export function ListScriptTypes():Promise<Array<ra2.ScriptType>>;
+
+export function ListSounds():Promise<Array<ra2.SoundEntry>>;
+
+export function ListTaskForces():Promise<Array<ra2.TaskForce>>;
+
+export function ListTeamTypes():Promise<Array<ra2.TeamType>>;
+
+export function MoveLayer(arg1:string,arg2:number):Promise<void>;
+
+export function NewLayer(arg1:string):Promise<main.Layer>;
+
+export function NewProject():Promise<void>;
+
 export function NewULID():Promise<string>;
 
 export function NextUnitID(arg1:string):Promise<number>;
 
 export function Open():Promise<void>;
 
+export function OpenAI():Promise<void>;
+
+export function OpenEVA():Promise<void>;
+
+export function OpenProject():Promise<void>;
+
+export function OpenRecent(arg1:string):Promise<void>;
+
+export function OpenSheet():Promise<main.SheetImport>;
+
+export function OpenSound():Promise<void>;
+
+export function PackageMod():Promise<void>;
+
+export function PreviewBulkEdit(arg1:ra2.BulkEdit):Promise<Array<ra2.BulkChange>>;
+
+export function Query(arg1:string):Promise<Array<ra2.QueryResult>>;
+
+export function Redo():Promise<void>;
+
+export function RemoveLayer(arg1:string):Promise<void>;
+
+export function RestoreRecovery():Promise<void>;
+
 export function Save():Promise<void>;
 
+export function SaveAI():Promise<void>;
+
+export function SaveAITriggerType(arg1:ra2.AITriggerType):Promise<ra2.AITriggerType>;
+
+export function SaveAs():Promise<void>;
+
+export function SaveEVA():Promise<void>;
+
+export function SaveEVAEvent(arg1:ra2.SoundEntry):Promise<void>;
+
+export function SaveProject():Promise<void>;
+
+export function SaveScriptType(arg1:ra2.ScriptType):Promise<ra2.ScriptType>;
+
+export function SaveSound():Promise<void>;
+
+export function SaveSoundEntry(arg1:ra2.SoundEntry):Promise<void>;
+
+export function SaveTaskForce(arg1:ra2.TaskForce):Promise<ra2.TaskForce>;
+
+export function SaveTeamType(arg1:ra2.TeamType):Promise<ra2.TeamType>;
+
 export function SaveUnit(arg1:main.Unit):Promise<void>;
 
+export function SaveUnitTable(arg1:Array<main.TableEdit>):Promise<void>;
+
+export function SetGameDir():Promise<void>;
+
+export function SetLayerEnabled(arg1:string,arg2:boolean):Promise<void>;
+
+export function SetLintRule(arg1:string,arg2:boolean,arg3:string):Promise<void>;
+
+export function SetWriteTarget(arg1:string):Promise<void>;
+
+export function SuggestUnitKeys(arg1:string,arg2:string):Promise<Array<string>>;
+
+export function Undo():Promise<void>;
+
 export function UserRules():Promise<string>;
+
+export function ValidateAI():Promise<Array<ra2.Problem>>;
+
+export function ValidateSounds():Promise<Array<ra2.Problem>>;
@@ -2,14 +2,122 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddLayer() {
+  return window['go']['main']['App']['AddLayer']();
+}
+
+export function ApplyBulkEdit(arg1) {
+  return window['go']['main']['App']['ApplyBulkEdit'](arg1);
+}
+
+export function ClearGameDir() {
+  return window['go']['main']['App']['ClearGameDir']();
+}
+
+export function CombatMatrix(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['CombatMatrix'](arg1, arg2, arg3, arg4);
+}
+
+export function DeleteAITriggerType(arg1) {
+  return window['go']['main']['App']['DeleteAITriggerType'](arg1);
+}
+
+export function DeleteEVAEvent(arg1) {
+  return window['go']['main']['App']['DeleteEVAEvent'](arg1);
+}
+
+export function DeleteScriptType(arg1) {
+  return window['go']['main']['App']['DeleteScriptType'](arg1);
+}
+
+export function DeleteSoundEntry(arg1) {
+  return window['go']['main']['App']['DeleteSoundEntry'](arg1);
+}
+
+export function DeleteTaskForce(arg1) {
+  return window['go']['main']['App']['DeleteTaskForce'](arg1);
+}
+
+export function DeleteTeamType(arg1) {
+  return window['go']['main']['App']['DeleteTeamType'](arg1);
+}
+
 export function DeleteUnit(arg1, arg2) {
   return window['go']['main']['App']['DeleteUnit'](arg1, arg2);
 }
 
+export function DiscardRecovery() {
+  return window['go']['main']['App']['DiscardRecovery']();
+}
+
+export function Economy(arg1, arg2) {
+  return window['go']['main']['App']['Economy'](arg1, arg2);
+}
+
+export function ExportEconomy(arg1, arg2) {
+  return window['go']['main']['App']['ExportEconomy'](arg1, arg2);
+}
+
+export function ExportSheet(arg1, arg2) {
+  return window['go']['main']['App']['ExportSheet'](arg1, arg2);
+}
+
+export function ExportStructured(arg1) {
+  return window['go']['main']['App']['ExportStructured'](arg1);
+}
+
+export function GetAIFile() {
+  return window['go']['main']['App']['GetAIFile']();
+}
+
+export function GetEVAFile() {
+  return window['go']['main']['App']['GetEVAFile']();
+}
+
+export function GetGameFiles() {
+  return window['go']['main']['App']['GetGameFiles']();
+}
+
+export function GetRecovery() {
+  return window['go']['main']['App']['GetRecovery']();
+}
+
+export function GetSoundFile() {
+  return window['go']['main']['App']['GetSoundFile']();
+}
+
 export function GetUnit(arg1, arg2) {
   return window['go']['main']['App']['GetUnit'](arg1, arg2);
 }
 
+export function GetUnitTable(arg1, arg2) {
+  return window['go']['main']['App']['GetUnitTable'](arg1, arg2);
+}
+
+export function HasUnsavedChanges() {
+  return window['go']['main']['App']['HasUnsavedChanges']();
+}
+
+export function History() {
+  return window['go']['main']['App']['History']();
+}
+
+export function ImportSheet(arg1) {
+  return window['go']['main']['App']['ImportSheet'](arg1);
+}
+
+export function ImportStructured() {
+  return window['go']['main']['App']['ImportStructured']();
+}
+
+export function Lint(arg1) {
+  return window['go']['main']['App']['Lint'](arg1);
+}
+
+export function ListAITriggerTypes() {
+  return window['go']['main']['App']['ListAITriggerTypes']();
+}
+
 export function ListAllUnits() {
   return window['go']['main']['App']['ListAllUnits']();
 }
@@ -18,6 +126,50 @@ export function ListAvailableProperties(arg1) {
   return window['go']['main']['App']['ListAvailableProperties'](arg1);
 }
 
+export function ListEVAEvents() {
+  return window['go']['main']['App']['ListEVAEvents']();
+}
+
+export function ListLayers() {
+  return window['go']['main']['App']['ListLayers']();
+}
+
+export function ListLintRules() {
+  return window['go']['main']['App']['ListLintRules']();
+}
+
+export function ListRecentFiles() {
+  return window['go']['main']['App']['ListRecentFiles']();
+}
+
+export function ListScriptTypes() {
+  return window['go']['main']['App']['ListScriptTypes']();
+}
+
+export function ListSounds() {
+  return window['go']['main']['App']['ListSounds']();
+}
+
+export function ListTaskForces() {
+  return window['go']['main']['App']['ListTaskForces']();
+}
+
+export function ListTeamTypes() {
+  return window['go']['main']['App']['ListTeamTypes']();
+}
+
+export function MoveLayer(arg1, arg2) {
+  return window['go']['main']['App']['MoveLayer'](arg1, arg2);
+}
+
+export function NewLayer(arg1) {
+  return window['go']['main']['App']['NewLayer'](arg1);
+}
+
+export function NewProject() {
+  return window['go']['main']['App']['NewProject']();
+}
+
 export function NewULID() {
   return window['go']['main']['App']['NewULID']();
 }
@@ -30,14 +182,142 @@ export function Open() {
   return window['go']['main']['App']['Open']();
 }
 
+export function OpenAI() {
+  return window['go']['main']['App']['OpenAI']();
+}
+
+export function OpenEVA() {
+  return window['go']['main']['App']['OpenEVA']();
+}
+
+export function OpenProject() {
+  return window['go']['main']['App']['OpenProject']();
+}
+
+export function OpenRecent(arg1) {
+  return window['go']['main']['App']['OpenRecent'](arg1);
+}
+
+export function OpenSheet() {
+  return window['go']['main']['App']['OpenSheet']();
+}
+
+export function OpenSound() {
+  return window['go']['main']['App']['OpenSound']();
+}
+
+export function PackageMod() {
+  return window['go']['main']['App']['PackageMod']();
+}
+
+export function PreviewBulkEdit(arg1) {
+  return window['go']['main']['App']['PreviewBulkEdit'](arg1);
+}
+
+export function Query(arg1) {
+  return window['go']['main']['App']['Query'](arg1);
+}
+
+export function Redo() {
+  return window['go']['main']['App']['Redo']();
+}
+
+export function RemoveLayer(arg1) {
+  return window['go']['main']['App']['RemoveLayer'](arg1);
+}
+
+export function RestoreRecovery() {
+  return window['go']['main']['App']['RestoreRecovery']();
+}
+
 export function Save() {
   return window['go']['main']['App']['Save']();
 }
 
+export function SaveAI() {
+  return window['go']['main']['App']['SaveAI']();
+}
+
+export function SaveAITriggerType(arg1) {
+  return window['go']['main']['App']['SaveAITriggerType'](arg1);
+}
+
+export function SaveAs() {
+  return window['go']['main']['App']['SaveAs']();
+}
+
+export function SaveEVA() {
+  return window['go']['main']['App']['SaveEVA']();
+}
+
+export function SaveEVAEvent(arg1) {
+  return window['go']['main']['App']['SaveEVAEvent'](arg1);
+}
+
+export function SaveProject() {
+  return window['go']['main']['App']['SaveProject']();
+}
+
+export function SaveScriptType(arg1) {
+  return window['go']['main']['App']['SaveScriptType'](arg1);
+}
+
+export function SaveSound() {
+  return window['go']['main']['App']['SaveSound']();
+}
+
+export function SaveSoundEntry(arg1) {
+  return window['go']['main']['App']['SaveSoundEntry'](arg1);
+}
+
+export function SaveTaskForce(arg1) {
+  return window['go']['main']['App']['SaveTaskForce'](arg1);
+}
+
+export function SaveTeamType(arg1) {
+  return window['go']['main']['App']['SaveTeamType'](arg1);
+}
+
 export function SaveUnit(arg1) {
   return window['go']['main']['App']['SaveUnit'](arg1);
 }
 
+export function SaveUnitTable(arg1) {
+  return window['go']['main']['App']['SaveUnitTable'](arg1);
+}
+
+export function SetGameDir() {
+  return window['go']['main']['App']['SetGameDir']();
+}
+
+export function SetLayerEnabled(arg1, arg2) {
+  return window['go']['main']['App']['SetLayerEnabled'](arg1, arg2);
+}
+
+export function SetLintRule(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetLintRule'](arg1, arg2, arg3);
+}
+
+export function SetWriteTarget(arg1) {
+  return window['go']['main']['App']['SetWriteTarget'](arg1);
+}
+
+export function SuggestUnitKeys(arg1, arg2) {
+  return window['go']['main']['App']['SuggestUnitKeys'](arg1, arg2);
+}
+
+export function Undo() {
+  return window['go']['main']['App']['Undo']();
+}
+
 export function UserRules() {
   return window['go']['main']['App']['UserRules']();
 }
+
+export function ValidateAI() {
+  return window['go']['main']['App']['ValidateAI']();
+}
+
+export function ValidateSounds() {
+  return window['go']['main']['App']['ValidateSounds']();
+}
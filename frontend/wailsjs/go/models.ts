@@ -1,11 +1,171 @@
+export namespace lint {
+	
+	export class Issue {
+	    section: string;
+	    key?: string;
+	    message: string;
+	    rule: string;
+	    severity: string;
+	    suggestions?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Issue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.section = source["section"];
+	        this.key = source["key"];
+	        this.message = source["message"];
+	        this.rule = source["rule"];
+	        this.severity = source["severity"];
+	        this.suggestions = source["suggestions"];
+	    }
+	}
+
+}
+
 export namespace main {
 	
+	export class Document {
+	    name: string;
+	    path: string;
+	    dirty: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Document(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.dirty = source["dirty"];
+	    }
+	}
+	export class EconomyReport {
+	    rows: ra2.EconomyRow[];
+	    power: ra2.PowerBalance;
+	
+	    static createFrom(source: any = {}) {
+	        return new EconomyReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rows = this.convertValues(source["rows"], ra2.EconomyRow);
+	        this.power = this.convertValues(source["power"], ra2.PowerBalance);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GameFiles {
+	    dir: string;
+	    rules: string;
+	    art: string;
+	    ai: string;
+	    sound: string;
+	    eva: string;
+	    csf: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GameFiles(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dir = source["dir"];
+	        this.rules = source["rules"];
+	        this.art = source["art"];
+	        this.ai = source["ai"];
+	        this.sound = source["sound"];
+	        this.eva = source["eva"];
+	        this.csf = source["csf"];
+	    }
+	}
+	export class HistoryEntry {
+	    label: string;
+	    done: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.done = source["done"];
+	    }
+	}
+	export class Layer {
+	    id: string;
+	    name: string;
+	    path: string;
+	    enabled: boolean;
+	    read_only: boolean;
+	    target: boolean;
+	    map: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Layer(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.enabled = source["enabled"];
+	        this.read_only = source["read_only"];
+	        this.target = source["target"];
+	        this.map = source["map"];
+	    }
+	}
+	export class LintRule {
+	    id: string;
+	    severity: string;
+	    desc: string;
+	    disabled: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new LintRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.severity = source["severity"];
+	        this.desc = source["desc"];
+	        this.disabled = source["disabled"];
+	    }
+	}
 	export class Property {
 	    ukey: string;
 	    key: string;
 	    value: string;
 	    comment: string;
 	    desc?: string;
+	    suggestions?: string[];
+	    action: string;
+	    origin?: string;
+	    source_id: string;
+	    source: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new Property(source);
@@ -18,6 +178,144 @@ export namespace main {
 	        this.value = source["value"];
 	        this.comment = source["comment"];
 	        this.desc = source["desc"];
+	        this.suggestions = source["suggestions"];
+	        this.action = source["action"];
+	        this.origin = source["origin"];
+	        this.source_id = source["source_id"];
+	        this.source = source["source"];
+	    }
+	}
+	export class Recovery {
+	    // Go type: time
+	    saved_at: any;
+	    layers: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Recovery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.saved_at = this.convertValues(source["saved_at"], null);
+	        this.layers = source["layers"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SheetImport {
+	    path: string;
+	    changes: ra2.BulkChange[];
+	    problems: ra2.Problem[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SheetImport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.changes = this.convertValues(source["changes"], ra2.BulkChange);
+	        this.problems = this.convertValues(source["problems"], ra2.Problem);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TableCell {
+	    value: string;
+	    default: boolean;
+	    source_id: string;
+	    source: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TableCell(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.value = source["value"];
+	        this.default = source["default"];
+	        this.source_id = source["source_id"];
+	        this.source = source["source"];
+	    }
+	}
+	export class TableColumn {
+	    key: string;
+	    cells: TableCell[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TableColumn(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.cells = this.convertValues(source["cells"], TableCell);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TableEdit {
+	    unit: string;
+	    key: string;
+	    value: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TableEdit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.unit = source["unit"];
+	        this.key = source["key"];
+	        this.value = source["value"];
 	    }
 	}
 	export class Unit {
@@ -58,6 +356,470 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class UnitTable {
+	    units: string[];
+	    ui_names: string[];
+	    columns: TableColumn[];
+	
+	    static createFrom(source: any = {}) {
+	        return new UnitTable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.units = source["units"];
+	        this.ui_names = source["ui_names"];
+	        this.columns = this.convertValues(source["columns"], TableColumn);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace ra2 {
+	
+	export class AITriggerType {
+	    id: string;
+	    name: string;
+	    team1: string;
+	    house: string;
+	    tech_level: number;
+	    condition_type: number;
+	    condition_object: string;
+	    comparator: string;
+	    weight: number;
+	    min_weight: number;
+	    max_weight: number;
+	    skirmish: boolean;
+	    side: number;
+	    base_defense: boolean;
+	    team2: string;
+	    easy: boolean;
+	    normal: boolean;
+	    hard: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new AITriggerType(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.team1 = source["team1"];
+	        this.house = source["house"];
+	        this.tech_level = source["tech_level"];
+	        this.condition_type = source["condition_type"];
+	        this.condition_object = source["condition_object"];
+	        this.comparator = source["comparator"];
+	        this.weight = source["weight"];
+	        this.min_weight = source["min_weight"];
+	        this.max_weight = source["max_weight"];
+	        this.skirmish = source["skirmish"];
+	        this.side = source["side"];
+	        this.base_defense = source["base_defense"];
+	        this.team2 = source["team2"];
+	        this.easy = source["easy"];
+	        this.normal = source["normal"];
+	        this.hard = source["hard"];
+	    }
+	}
+	export class BulkChange {
+	    section: string;
+	    type: string;
+	    key: string;
+	    old: string;
+	    new: string;
+	    action?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BulkChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.section = source["section"];
+	        this.type = source["type"];
+	        this.key = source["key"];
+	        this.old = source["old"];
+	        this.new = source["new"];
+	        this.action = source["action"];
+	    }
+	}
+	export class BulkEdit {
+	    query: string;
+	    op: string;
+	    key: string;
+	    value: string;
+	    action?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BulkEdit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.query = source["query"];
+	        this.op = source["op"];
+	        this.key = source["key"];
+	        this.value = source["value"];
+	        this.action = source["action"];
+	    }
+	}
+	export class CombatCell {
+	    slot: string;
+	    weapon: string;
+	    range: number;
+	    damage: number;
+	    dps: number;
+	    ttk: number;
+	    shots: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CombatCell(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.slot = source["slot"];
+	        this.weapon = source["weapon"];
+	        this.range = source["range"];
+	        this.damage = source["damage"];
+	        this.dps = source["dps"];
+	        this.ttk = source["ttk"];
+	        this.shots = source["shots"];
+	    }
+	}
+	export class CombatMatrix {
+	    attackers: string[];
+	    defenders: string[];
+	    cells: CombatCell[][];
+	
+	    static createFrom(source: any = {}) {
+	        return new CombatMatrix(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.attackers = source["attackers"];
+	        this.defenders = source["defenders"];
+	        this.cells = this.convertValues(source["cells"], CombatCell);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EconomyOptions {
+	    factories: number;
+	    armor: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EconomyOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.factories = source["factories"];
+	        this.armor = source["armor"];
+	    }
+	}
+	export class EconomyRow {
+	    name: string;
+	    type: string;
+	    cost: number;
+	    strength: number;
+	    build_time: number;
+	    cost_per_hp: number;
+	    dps: number;
+	    cost_per_dps: number;
+	    power: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EconomyRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.cost = source["cost"];
+	        this.strength = source["strength"];
+	        this.build_time = source["build_time"];
+	        this.cost_per_hp = source["cost_per_hp"];
+	        this.dps = source["dps"];
+	        this.cost_per_dps = source["cost_per_dps"];
+	        this.power = source["power"];
+	    }
+	}
+	export class PowerBalance {
+	    output: number;
+	    drain: number;
+	    balance: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PowerBalance(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.output = source["output"];
+	        this.drain = source["drain"];
+	        this.balance = source["balance"];
+	    }
+	}
+	export class Problem {
+	    section: string;
+	    key?: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Problem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.section = source["section"];
+	        this.key = source["key"];
+	        this.message = source["message"];
+	    }
+	}
+	export class Property {
+	    key: string;
+	    value: string;
+	    comment: string;
+	    name: string;
+	    desc: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Property(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.value = source["value"];
+	        this.comment = source["comment"];
+	        this.name = source["name"];
+	        this.desc = source["desc"];
+	    }
+	}
+	export class QueryResult {
+	    section: string;
+	    type: string;
+	    values: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueryResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.section = source["section"];
+	        this.type = source["type"];
+	        this.values = source["values"];
+	    }
+	}
+	export class ScriptAction {
+	    action: number;
+	    argument: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScriptAction(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.action = source["action"];
+	        this.argument = source["argument"];
+	    }
+	}
+	export class ScriptType {
+	    id: string;
+	    name: string;
+	    actions: ScriptAction[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ScriptType(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.actions = this.convertValues(source["actions"], ScriptAction);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SoundEntry {
+	    name: string;
+	    properties: Property[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SoundEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.properties = this.convertValues(source["properties"], Property);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskForceMember {
+	    count: number;
+	    type: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskForceMember(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.count = source["count"];
+	        this.type = source["type"];
+	    }
+	}
+	export class TaskForce {
+	    id: string;
+	    name: string;
+	    group: number;
+	    members: TaskForceMember[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskForce(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.group = source["group"];
+	        this.members = this.convertValues(source["members"], TaskForceMember);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class TeamType {
+	    id: string;
+	    name: string;
+	    task_force: string;
+	    script: string;
+	    properties: Property[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TeamType(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.task_force = source["task_force"];
+	        this.script = source["script"];
+	        this.properties = this.convertValues(source["properties"], Property);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
@@ -0,0 +1,231 @@
+// Package docs 由规则生成面向玩家的单位资料页，输出为 Markdown 或静态 HTML。
+package docs
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/pkg/errors"
+
+	"ra2-ini-editor/internal/fileutil"
+	"ra2-ini-editor/internal/ra2"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Format 是资料页的输出格式。
+type Format string
+
+const (
+	FormatMarkdown Format = "md"
+	FormatHTML     Format = "html"
+)
+
+// ParseFormat 解析命令行中的格式名。
+func ParseFormat(name string) (Format, bool) {
+	switch strings.ToLower(name) {
+	case "md", "markdown":
+		return FormatMarkdown, true
+	case "html":
+		return FormatHTML, true
+	default:
+		return "", false
+	}
+}
+
+// statKeys 是资料页中列出的基本属性。
+var statKeys = []string{"Cost", "Strength", "Armor", "Speed", "Sight", "TechLevel"}
+
+// Stat 是一项基本属性，Desc 取自 schema 描述的第一句。
+type Stat struct {
+	Key   string
+	Value string
+	Desc  string
+}
+
+// Link 是对另一个 section 的引用，File 为其资料页的文件名，没有资料页时为空。
+type Link struct {
+	Name string
+	File string
+}
+
+// Page 是一个单位的资料页。
+type Page struct {
+	Name          string
+	UIName        string
+	Type          ra2.UnitType
+	File          string
+	Stats         []Stat
+	Weapons       []ra2.Weapon
+	Prerequisites []Link
+	Owners        []string
+}
+
+// Generator 由合并后的规则生成资料页。Translation 与 Schema 可以为 nil，此时省略显示名与属性描述。
+type Generator struct {
+	Rules       *ra2.Rules
+	Translation *ra2.Translation
+	Schema      *ra2.Schema
+}
+
+// Pages 为 units 生成资料页，顺序与 units 一致。
+func (g *Generator) Pages(units []*ra2.Unit) []*Page {
+	files := make(map[string]string, len(units))
+	for _, unit := range units {
+		files[unit.Name] = fileName(unit.Name)
+	}
+	pages := make([]*Page, 0, len(units))
+	for _, unit := range units {
+		pages = append(pages, g.page(unit, files))
+	}
+	return pages
+}
+
+func (g *Generator) page(unit *ra2.Unit, files map[string]string) *Page {
+	page := &Page{
+		Name:    unit.Name,
+		UIName:  unit.Name,
+		Type:    unit.Type,
+		File:    files[unit.Name],
+		Weapons: g.Rules.Weapons(unit),
+		Owners:  splitList(unit.Get("Owner")),
+	}
+	if g.Translation != nil {
+		if name, ok := g.Translation.Lookup(unit.UIName()); ok && name != "" {
+			page.UIName = name
+		}
+	}
+	for _, key := range statKeys {
+		stat := Stat{Key: key, Value: strings.TrimSpace(unit.Get(key))}
+		if g.Schema != nil {
+			if flag, ok := g.Schema.Flag(unit.Type, unit.Name, key); ok {
+				if stat.Value == "" {
+					stat.Value = flag.DefaultValue
+				}
+				stat.Desc = summary(flag.Desc)
+			}
+		}
+		page.Stats = append(page.Stats, stat)
+	}
+	for _, name := range splitList(unit.Get("Prerequisite")) {
+		page.Prerequisites = append(page.Prerequisites, Link{Name: name, File: files[name]})
+	}
+	return page
+}
+
+// Write 将 pages 及索引页 index 写入目录 dir。
+func Write(dir string, format Format, pages []*Page) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return errors.WithStack(err)
+	}
+	var buf bytes.Buffer
+	if err := RenderIndex(&buf, format, pages); err != nil {
+		return err
+	}
+	if err := fileutil.WriteFileAtomic(filepath.Join(dir, "index."+string(format)), buf.Bytes(), false); err != nil {
+		return err
+	}
+	for _, page := range pages {
+		buf.Reset()
+		if err := RenderPage(&buf, format, page); err != nil {
+			return errors.Wrapf(err, "render %s", page.Name)
+		}
+		if err := fileutil.WriteFileAtomic(filepath.Join(dir, page.File+"."+string(format)), buf.Bytes(), false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderPage 以 format 格式输出单个单位的资料页。
+func RenderPage(w io.Writer, format Format, page *Page) error {
+	return render(w, format, "page", page)
+}
+
+// RenderIndex 以 format 格式输出列出所有 pages 的索引页。
+func RenderIndex(w io.Writer, format Format, pages []*Page) error {
+	return render(w, format, "index", pages)
+}
+
+type executor interface {
+	ExecuteTemplate(w io.Writer, name string, data any) error
+}
+
+var templates = map[Format]executor{
+	FormatMarkdown: texttemplate.Must(texttemplate.New("").Funcs(texttemplate.FuncMap{
+		"cell":   markdownCell,
+		"number": formatNumber,
+		"stat":   stat,
+	}).ParseFS(templateFS, "templates/*.md.tmpl")),
+	FormatHTML: htmltemplate.Must(htmltemplate.New("").Funcs(htmltemplate.FuncMap{
+		"number": formatNumber,
+		"stat":   stat,
+	}).ParseFS(templateFS, "templates/*.html.tmpl")),
+}
+
+func render(w io.Writer, format Format, name string, data any) error {
+	tmpl, ok := templates[format]
+	if !ok {
+		return errors.Errorf("unknown docs format %q", format)
+	}
+	return errors.WithStack(tmpl.ExecuteTemplate(w, name+"."+string(format)+".tmpl", data))
+}
+
+// stat 返回 page 中 key 属性的值。
+func stat(page *Page, key string) string {
+	for _, s := range page.Stats {
+		if s.Key == key {
+			return s.Value
+		}
+	}
+	return ""
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// markdownCell 转义 Markdown 表格单元格中的竖线与换行。
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// fileName 返回 section 名对应的文件名，不含扩展名。
+func fileName(name string) string {
+	return unsafeChars.ReplaceAllString(name, "_")
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// summary 返回 schema 描述的第一句。部分描述以 wiki 的目录开头，无法截取，直接忽略。
+func summary(desc string) string {
+	desc = strings.Join(strings.Fields(desc), " ")
+	if strings.HasPrefix(desc, "Contents") || strings.HasPrefix(desc, "目录") {
+		return ""
+	}
+	for _, sep := range []string{"。", ". "} {
+		if i := strings.Index(desc, sep); i >= 0 {
+			desc = desc[:i+len(strings.TrimSpace(sep))]
+		}
+	}
+	return desc
+}
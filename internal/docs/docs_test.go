@@ -0,0 +1,121 @@
+package docs
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ra2-ini-editor/internal/ra2"
+)
+
+const testRules = `[VehicleTypes]
+0=HTNK
+[BuildingTypes]
+0=NAWEAP
+[HTNK]
+UIName=Name:HTNK
+Cost=900
+Armor=heavy
+Prerequisite=NAWEAP,NARADR
+Owner=Russians,Arabs
+Primary=120mm
+[NAWEAP]
+Name=Soviet War Factory|Test
+[120mm]
+Damage=90
+ROF=65
+Range=5.75
+Warhead=AP
+`
+
+func newTestGenerator(t *testing.T) *Generator {
+	rules, err := ra2.NewRules(io.NopCloser(strings.NewReader(testRules)))
+	require.NoError(t, err)
+	translation, err := ra2.LoadTranslation(io.NopCloser(strings.NewReader("[en]\nName:HTNK=Rhino Tank\n")), "en")
+	require.NoError(t, err)
+	schema := &ra2.Schema{Flags: []ra2.IniFlag{
+		{Category: "TechnoTypes", Key: "Cost", ValueType: "int", DefaultValue: "0", Desc: "Sets the price. More text follows."},
+		{Category: "ObjectTypes", Key: "Strength", ValueType: "int", DefaultValue: "0", Desc: "Contents 1 On ObjectTypes"},
+	}}
+	return &Generator{Rules: rules, Translation: translation, Schema: schema}
+}
+
+func TestGenerator_Pages(t *testing.T) {
+	g := newTestGenerator(t)
+	pages := g.Pages(g.Rules.Units())
+	require.Len(t, pages, 2)
+
+	htnk := pages[0]
+	assert.Equal(t, "Rhino Tank", htnk.UIName)
+	assert.Equal(t, ra2.UnitTypeVehicle, htnk.Type)
+	assert.Equal(t, []Stat{
+		{Key: "Cost", Value: "900", Desc: "Sets the price."},
+		{Key: "Strength", Value: "0"},
+		{Key: "Armor", Value: "heavy"},
+		{Key: "Speed"},
+		{Key: "Sight"},
+		{Key: "TechLevel"},
+	}, htnk.Stats)
+	assert.Equal(t, []Link{{Name: "NAWEAP", File: "NAWEAP"}, {Name: "NARADR"}}, htnk.Prerequisites)
+	assert.Equal(t, []string{"Russians", "Arabs"}, htnk.Owners)
+	require.Len(t, htnk.Weapons, 1)
+	assert.Equal(t, 90, htnk.Weapons[0].Damage)
+
+	// 没有翻译时使用 section 名
+	assert.Equal(t, "NAWEAP", pages[1].UIName)
+	assert.Nil(t, pages[1].Weapons)
+}
+
+func TestRenderPage(t *testing.T) {
+	g := newTestGenerator(t)
+	page := g.Pages([]*ra2.Unit{g.Rules.UnitByName("HTNK")})[0]
+	page.Owners = append(page.Owners, "<Yuri|Country>")
+
+	var md bytes.Buffer
+	require.NoError(t, RenderPage(&md, FormatMarkdown, page))
+	assert.Contains(t, md.String(), "# Rhino Tank (HTNK)\n")
+	assert.Contains(t, md.String(), "| Cost | 900 | Sets the price. |\n")
+	assert.Contains(t, md.String(), "| Primary | 120mm | 90 | 65 | 5.75 | 1 | AP |\n")
+	// 只有生成了资料页的前提建筑才有链接
+	assert.Contains(t, md.String(), "- NARADR\n")
+
+	var html bytes.Buffer
+	require.NoError(t, RenderPage(&html, FormatHTML, page))
+	assert.Contains(t, html.String(), "<td>Primary</td><td>120mm</td><td>90</td><td>65</td><td>5.75</td>")
+	assert.Contains(t, html.String(), "<li>&lt;Yuri|Country&gt;</li>")
+}
+
+func TestWrite(t *testing.T) {
+	g := newTestGenerator(t)
+	pages := g.Pages(g.Rules.Units())
+	pages[1].File = "NA_WEAP"
+	dir := t.TempDir()
+	for _, format := range []Format{FormatMarkdown, FormatHTML} {
+		require.NoError(t, Write(dir, format, pages))
+		for _, name := range []string{"index", "HTNK", "NA_WEAP"} {
+			assert.FileExists(t, filepath.Join(dir, name+"."+string(format)))
+		}
+	}
+	index, err := os.ReadFile(filepath.Join(dir, "index.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "| [HTNK](HTNK.md) | Rhino Tank | vehicle | 900 |")
+}
+
+func TestFileName(t *testing.T) {
+	assert.Equal(t, "HTNK", fileName("HTNK"))
+	assert.Equal(t, "GA_SPY_2", fileName("GA/SPY.2"))
+}
+
+func TestParseFormat(t *testing.T) {
+	format, ok := ParseFormat("Markdown")
+	assert.True(t, ok)
+	assert.Equal(t, FormatMarkdown, format)
+	_, ok = ParseFormat("pdf")
+	assert.False(t, ok)
+}
@@ -0,0 +1,60 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// BackupSuffix 是 WriteFileAtomic 备份旧文件时使用的后缀
+const BackupSuffix = ".bak"
+
+// WriteFileAtomic 先写入同目录下的临时文件，再重命名为 filename，
+// 写入失败时不会破坏原文件。backup 为 true 且原文件存在时，原文件会被复制为 filename.bak。
+func WriteFileAtomic(filename string, data []byte, backup bool) error {
+	perm := os.FileMode(0o644)
+	if info, err := os.Stat(filename); err == nil {
+		perm = info.Mode().Perm()
+		if backup {
+			if err := copyFile(filename, filename+BackupSuffix, perm); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return errors.WithStack(err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.WithStack(err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return errors.WithStack(err)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, perm)
+}
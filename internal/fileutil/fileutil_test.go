@@ -0,0 +1,29 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "rulesmd.ini")
+
+	assert.NoError(t, WriteFileAtomic(filename, []byte("[E1]\nCost=100\n"), true))
+	assert.NoFileExists(t, filename+BackupSuffix)
+
+	assert.NoError(t, WriteFileAtomic(filename, []byte("[E1]\nCost=150\n"), true))
+	got, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, "[E1]\nCost=150\n", string(got))
+	backup, err := os.ReadFile(filename + BackupSuffix)
+	assert.NoError(t, err)
+	assert.Equal(t, "[E1]\nCost=100\n", string(backup))
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2, "temporary file should be removed")
+}
@@ -0,0 +1,120 @@
+package history
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Command 是一次已经执行、可以撤销和重做的修改。
+type Command interface {
+	Label() string
+	Undo() error
+	Redo() error
+}
+
+type funcCommand struct {
+	label string
+	undo  func() error
+	redo  func() error
+}
+
+// NewCommand 使用一对闭包创建 Command。
+func NewCommand(label string, undo, redo func() error) Command {
+	return &funcCommand{
+		label: label,
+		undo:  undo,
+		redo:  redo,
+	}
+}
+
+func (c *funcCommand) Label() string {
+	return c.label
+}
+
+func (c *funcCommand) Undo() error {
+	return c.undo()
+}
+
+func (c *funcCommand) Redo() error {
+	return c.redo()
+}
+
+type Entry struct {
+	Label string
+	Done  bool // false 表示已被撤销，可以重做
+}
+
+// History 记录修改历史，超过 limit 时丢弃最早的记录。
+type History struct {
+	limit  int
+	done   []Command
+	undone []Command
+}
+
+func New(limit int) *History {
+	return &History{
+		limit: limit,
+	}
+}
+
+// Push 记录一次已经执行的修改，并清空重做栈。
+func (h *History) Push(cmd Command) {
+	h.done = append(h.done, cmd)
+	if h.limit > 0 && len(h.done) > h.limit {
+		h.done = h.done[len(h.done)-h.limit:]
+	}
+	h.undone = nil
+}
+
+func (h *History) CanUndo() bool {
+	return len(h.done) > 0
+}
+
+func (h *History) CanRedo() bool {
+	return len(h.undone) > 0
+}
+
+// Undo 撤销最近一次修改，失败时历史保持不变。
+func (h *History) Undo() (Command, error) {
+	if !h.CanUndo() {
+		return nil, errors.New("nothing to undo")
+	}
+	cmd := h.done[len(h.done)-1]
+	if err := cmd.Undo(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	h.done = h.done[:len(h.done)-1]
+	h.undone = append(h.undone, cmd)
+	return cmd, nil
+}
+
+// Redo 重做最近一次撤销的修改，失败时历史保持不变。
+func (h *History) Redo() (Command, error) {
+	if !h.CanRedo() {
+		return nil, errors.New("nothing to redo")
+	}
+	cmd := h.undone[len(h.undone)-1]
+	if err := cmd.Redo(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	h.undone = h.undone[:len(h.undone)-1]
+	h.done = append(h.done, cmd)
+	return cmd, nil
+}
+
+// Entries 按时间顺序返回所有记录，已撤销的记录排在最后。
+func (h *History) Entries() []Entry {
+	entries := make([]Entry, 0, len(h.done)+len(h.undone))
+	for _, cmd := range h.done {
+		entries = append(entries, Entry{Label: cmd.Label(), Done: true})
+	}
+	for i := len(h.undone) - 1; i >= 0; i-- {
+		entries = append(entries, Entry{Label: h.undone[i].Label(), Done: false})
+	}
+	return entries
+}
+
+// Clear 清空所有记录。
+func (h *History) Clear() {
+	h.done = nil
+	h.undone = nil
+}
@@ -0,0 +1,87 @@
+package history
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type counter struct {
+	value int
+}
+
+func (c *counter) add(h *History, n int) {
+	c.value += n
+	h.Push(NewCommand(
+		fmt.Sprintf("add %d", n),
+		func() error { c.value -= n; return nil },
+		func() error { c.value += n; return nil },
+	))
+}
+
+func TestHistory_UndoRedo(t *testing.T) {
+	h := New(10)
+	c := &counter{}
+	c.add(h, 1)
+	c.add(h, 2)
+	assert.Equal(t, 3, c.value)
+
+	cmd, err := h.Undo()
+	assert.NoError(t, err)
+	assert.Equal(t, "add 2", cmd.Label())
+	assert.Equal(t, 1, c.value)
+
+	cmd, err = h.Redo()
+	assert.NoError(t, err)
+	assert.Equal(t, "add 2", cmd.Label())
+	assert.Equal(t, 3, c.value)
+
+	_, err = h.Redo()
+	assert.Error(t, err)
+}
+
+func TestHistory_PushClearsRedo(t *testing.T) {
+	h := New(10)
+	c := &counter{}
+	c.add(h, 1)
+	c.add(h, 2)
+	_, err := h.Undo()
+	assert.NoError(t, err)
+	assert.Equal(t, []Entry{{Label: "add 1", Done: true}, {Label: "add 2", Done: false}}, h.Entries())
+
+	c.add(h, 5)
+	assert.False(t, h.CanRedo())
+	assert.Equal(t, []Entry{{Label: "add 1", Done: true}, {Label: "add 5", Done: true}}, h.Entries())
+}
+
+func TestHistory_Limit(t *testing.T) {
+	h := New(2)
+	c := &counter{}
+	c.add(h, 1)
+	c.add(h, 2)
+	c.add(h, 3)
+	assert.Len(t, h.Entries(), 2)
+
+	_, err := h.Undo()
+	assert.NoError(t, err)
+	_, err = h.Undo()
+	assert.NoError(t, err)
+	_, err = h.Undo()
+	assert.Error(t, err)
+	assert.Equal(t, 1, c.value)
+}
+
+func TestHistory_UndoError(t *testing.T) {
+	h := New(10)
+	h.Push(NewCommand("broken",
+		func() error { return errors.New("boom") },
+		func() error { return nil },
+	))
+
+	_, err := h.Undo()
+	assert.Error(t, err)
+	assert.True(t, h.CanUndo())
+	assert.False(t, h.CanRedo())
+}
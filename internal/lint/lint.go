@@ -0,0 +1,188 @@
+// Package lint 检查 mod 规则中类型检查之外的常见质量问题，例如重复注册、拼错的 key、缺少翻译的名称。
+// 每条规则有稳定的 ID 与默认级别，项目可以关闭规则或调整级别，也可以在 INI 注释中抑制单处问题：
+//
+//	; lint:ignore unknown-key
+//	Strenght=100
+//
+// 写在 key 前或行尾的注释只作用于该 key，写在 section 前的注释作用于整个 section，
+// 省略规则 ID 时抑制所有规则。
+package lint
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"ra2-ini-editor/internal/project"
+	"ra2-ini-editor/internal/ra2"
+)
+
+// Severity 是问题的级别。
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+func ParseSeverity(name string) (Severity, bool) {
+	switch Severity(name) {
+	case SeverityError, SeverityWarning, SeverityInfo:
+		return Severity(name), true
+	default:
+		return "", false
+	}
+}
+
+func (s Severity) rank() int {
+	switch s {
+	case SeverityError:
+		return 2
+	case SeverityWarning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AtLeast 返回 s 是否不低于 min。
+func (s Severity) AtLeast(min Severity) bool {
+	return s.rank() >= min.rank()
+}
+
+// Issue 是一条规则发现的问题。
+type Issue struct {
+	ra2.Problem
+	Rule        string   `json:"rule"`
+	Severity    Severity `json:"severity"`
+	Suggestions []string `json:"suggestions,omitempty"` // 可能的正确写法，如拼错的 key
+}
+
+// Context 是规则检查的数据。除 Rules 与 Schema 外都可以为 nil，此时跳过依赖它们的规则。
+type Context struct {
+	Rules        *ra2.Rules // 合并后的规则
+	Schema       *ra2.Schema
+	Translation  *ra2.Translation
+	AI           *ra2.AI
+	Sounds       *ra2.SoundList
+	EVA          *ra2.SoundList
+	Engine       string      // 项目的引擎扩展，为空表示原版
+	EngineSchema *ra2.Schema // 引擎扩展的 flag，用于发现原版项目中使用的扩展 key
+}
+
+// Rule 是一条 lint 规则。Check 返回的问题由 Run 填写规则 ID 与级别。
+type Rule struct {
+	ID       string
+	Severity Severity // 默认级别
+	Desc     string
+	Check    func(ctx *Context) []Issue
+	// Unscoped 表示问题的 section 不在规则栈中，如 AI 文件的 ID，不按 Options.Scope 过滤
+	Unscoped bool
+}
+
+// Options 控制 Run 运行哪些规则以及报告哪些问题。
+type Options struct {
+	Disabled   []string            // 关闭的规则 ID
+	Severities map[string]Severity // 按规则 ID 覆盖默认级别
+	// Scope 返回 section 中 key 的问题是否需要报告，key 为空表示整个 section。为 nil 时报告所有问题。
+	Scope func(section, key string) bool
+}
+
+// Run 依次运行 rules 中未关闭的规则，返回未被注释抑制且在 Scope 内的问题。
+// Options 中引用了 rules 中没有的规则 ID 时返回错误，以便发现项目设置中的拼写错误。
+func Run(ctx *Context, rules []Rule, opts Options) ([]Issue, error) {
+	for _, id := range opts.Disabled {
+		if !slices.ContainsFunc(rules, func(r Rule) bool { return r.ID == id }) {
+			return nil, errors.Errorf("unknown lint rule %q", id)
+		}
+	}
+	for id := range opts.Severities {
+		if !slices.ContainsFunc(rules, func(r Rule) bool { return r.ID == id }) {
+			return nil, errors.Errorf("unknown lint rule %q", id)
+		}
+	}
+
+	var issues []Issue
+	for _, rule := range rules {
+		if slices.Contains(opts.Disabled, rule.ID) {
+			continue
+		}
+		severity := rule.Severity
+		if s, ok := opts.Severities[rule.ID]; ok {
+			severity = s
+		}
+		for _, issue := range rule.Check(ctx) {
+			if opts.Scope != nil && !rule.Unscoped && !opts.Scope(issue.Section, issue.Key) {
+				continue
+			}
+			if suppressed(ctx.Rules, issue.Section, issue.Key, rule.ID) {
+				continue
+			}
+			issue.Rule = rule.ID
+			issue.Severity = severity
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// LayerScope 返回只报告覆盖层中内容的 Scope：section 须在某个启用的可写层中定义，
+// key 的生效值须来自可写层，或者下层没有该 key。基础规则中原有的问题因此不会报告。
+func LayerScope(layers *ra2.LayerStack) func(section, key string) bool {
+	return func(section, key string) bool {
+		defined := false
+		for _, layer := range layers.Layers() {
+			if layer.Enabled && !layer.ReadOnly && layer.Rules.HasSection(section) {
+				defined = true
+				break
+			}
+		}
+		if !defined || key == "" {
+			return defined
+		}
+		source := layers.Source(section, key)
+		return source == nil || !source.ReadOnly
+	}
+}
+
+// ProjectOptions 由项目的 lint 设置生成 Options，级别名称无效时返回错误。
+func ProjectOptions(cfg project.Lint) (Options, error) {
+	opts := Options{
+		Disabled:   cfg.Disabled,
+		Severities: make(map[string]Severity, len(cfg.Severities)),
+	}
+	for id, name := range cfg.Severities {
+		severity, ok := ParseSeverity(name)
+		if !ok {
+			return Options{}, errors.Errorf("lint rule %s: unknown severity %q", id, name)
+		}
+		opts.Severities[id] = severity
+	}
+	return opts, nil
+}
+
+const ignoreDirective = "lint:ignore"
+
+// suppressed 返回 section 或其中 key 的注释是否抑制了规则 id。
+func suppressed(rules *ra2.Rules, section, key, id string) bool {
+	comments := []string{rules.Comment(section, "")}
+	if key != "" {
+		comments = append(comments, rules.Comment(section, key))
+	}
+	for _, comment := range comments {
+		for _, line := range strings.Split(comment, "\n") {
+			line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), ";#"))
+			rest, ok := strings.CutPrefix(line, ignoreDirective)
+			if !ok {
+				continue
+			}
+			ids := strings.FieldsFunc(rest, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+			if len(ids) == 0 || slices.Contains(ids, id) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,200 @@
+package lint
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ra2-ini-editor/internal/project"
+	"ra2-ini-editor/internal/ra2"
+)
+
+func loadTestRules(t *testing.T, content string) *ra2.Rules {
+	rules, err := ra2.NewRules(io.NopCloser(strings.NewReader(content)))
+	require.NoError(t, err)
+	return rules
+}
+
+func newTestContext(t *testing.T, content string) *Context {
+	translation, err := ra2.LoadTranslation(io.NopCloser(strings.NewReader("[en]\nName:HTNK=Rhino Tank\n")), "en")
+	require.NoError(t, err)
+	return &Context{
+		Rules:       loadTestRules(t, content),
+		Translation: translation,
+		Schema: &ra2.Schema{Flags: []ra2.IniFlag{
+			{Category: "TechnoTypes", Key: "Cost", ValueType: "int"},
+			{Category: "TechnoTypes", Key: "TechLevel", ValueType: "int"},
+			{Category: "TechnoTypes", Key: "UIName", ValueType: "string"},
+			{Category: "ObjectTypes", Key: "Strength", ValueType: "int"},
+		}},
+		EngineSchema: &ra2.Schema{Flags: []ra2.IniFlag{
+			{Category: "TechnoTypes", Key: "ImmuneToEMP", ValueType: "boolean", Engine: "ares"},
+		}},
+	}
+}
+
+const testRules = `[VehicleTypes]
+0=HTNK
+1=MTNK
+2=HTNK
+3=GONE
+[HTNK]
+UIName=Name:HTNK
+Cost=900
+TechLevel=2
+Strength=400
+[MTNK]
+UIName=Name:MTNK
+Strenght=300
+TechLevel=2
+ImmuneToEMP=yes
+[ORPHAN]
+Strength=100
+Cost=500
+`
+
+func TestRun(t *testing.T) {
+	ctx := newTestContext(t, testRules)
+	issues, err := Run(ctx, DefaultRules(), Options{})
+	require.NoError(t, err)
+
+	type brief struct{ Rule, Section, Key, Message string }
+	got := make([]brief, 0, len(issues))
+	for _, issue := range issues {
+		got = append(got, brief{issue.Rule, issue.Section, issue.Key, issue.Message})
+	}
+	assert.Equal(t, []brief{
+		{"duplicate-id", "VehicleTypes", "2", "HTNK is already registered as 0"},
+		{"undefined-section", "VehicleTypes", "3", "GONE is registered but not defined"},
+		{"unregistered-section", "ORPHAN", "", "section looks like a unit but is not registered in any type list"},
+		{"unknown-key", "MTNK", "Strenght", "unknown key, did you mean Strength?"},
+		{"missing-uiname", "MTNK", "UIName", "label Name:MTNK is not in the string table"},
+		{"zero-cost", "MTNK", "Cost", "buildable at tech level 2 but costs nothing"},
+		{"ares-key", "MTNK", "ImmuneToEMP", "key requires ares but the project uses the original engine"},
+	}, got)
+	assert.Equal(t, SeverityError, issues[0].Severity)
+	assert.Equal(t, []string{"Strength"}, issues[3].Suggestions)
+
+	// Ares 项目中扩展的 key 是合法的
+	ctx.Engine = project.EngineAres
+	issues, err = Run(ctx, DefaultRules(), Options{Disabled: []string{"duplicate-id", "undefined-section"}})
+	require.NoError(t, err)
+	assert.NotContains(t, rulesOf(issues), "ares-key")
+	assert.NotContains(t, rulesOf(issues), "duplicate-id")
+}
+
+func rulesOf(issues []Issue) []string {
+	var ids []string
+	for _, issue := range issues {
+		ids = append(ids, issue.Rule)
+	}
+	return ids
+}
+
+func TestRun_Options(t *testing.T) {
+	ctx := newTestContext(t, testRules)
+
+	issues, err := Run(ctx, DefaultRules(), Options{Severities: map[string]Severity{"zero-cost": SeverityError}})
+	require.NoError(t, err)
+	for _, issue := range issues {
+		if issue.Rule == "zero-cost" {
+			assert.Equal(t, SeverityError, issue.Severity)
+		}
+	}
+
+	issues, err = Run(ctx, DefaultRules(), Options{Scope: func(section, key string) bool { return section == "HTNK" || section == "MTNK" && key == "" }})
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+
+	_, err = Run(ctx, DefaultRules(), Options{Disabled: []string{"no-such-rule"}})
+	assert.EqualError(t, err, `unknown lint rule "no-such-rule"`)
+
+	// 自定义规则
+	custom := Rule{ID: "no-orphan", Severity: SeverityInfo, Check: func(ctx *Context) []Issue {
+		return []Issue{newIssue("ORPHAN", "", "custom")}
+	}}
+	issues, err = Run(ctx, []Rule{custom}, Options{})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "no-orphan", issues[0].Rule)
+	assert.Equal(t, SeverityInfo, issues[0].Severity)
+}
+
+func TestRun_Suppression(t *testing.T) {
+	ctx := newTestContext(t, `[VehicleTypes]
+0=MTNK
+1=TNK2
+; lint:ignore
+[MTNK]
+Strenght=300
+[TNK2]
+; lint:ignore unknown-key, zero-cost
+Strenght=300
+Armr=heavy ; lint:ignore zero-cost
+`)
+	issues, err := Run(ctx, DefaultRules(), Options{})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "TNK2", issues[0].Section)
+	assert.Equal(t, "Armr", issues[0].Key)
+}
+
+func TestLayerScope(t *testing.T) {
+	base := ra2.NewLayer("base", loadTestRules(t, "[HTNK]\nCost=900\nArmor=heavy\n[MTNK]\nCost=700\n"))
+	base.ReadOnly = true
+	mod := ra2.NewLayer("mod", loadTestRules(t, "[HTNK]\nCost=1000\nStrenght=400\n"))
+	scope := LayerScope(ra2.NewLayerStack(base, mod))
+
+	tests := []struct {
+		section, key string
+		want         bool
+	}{
+		{"HTNK", "", true},
+		{"HTNK", "Cost", true},
+		{"HTNK", "Strenght", true},
+		{"HTNK", "Armor", false},
+		{"HTNK", "Speed", true},
+		{"MTNK", "", false},
+		{"MTNK", "Cost", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, scope(tt.section, tt.key), "%s %s", tt.section, tt.key)
+	}
+}
+
+func TestRun_UnscopedAI(t *testing.T) {
+	ctx := newTestContext(t, testRules)
+	ctx.AI = ra2.NewAI(loadTestRules(t, "[TaskForces]\n0=01000000-G\n[01000000-G]\n0=1,NOPE\n"))
+	base := ra2.NewLayer("base", ctx.Rules)
+	base.ReadOnly = true
+	mod := ra2.NewLayer("mod", ra2.NewEmptyRules())
+
+	// AI 文件的 ID 不在规则层中，默认范围内同样报告
+	issues, err := Run(ctx, DefaultRules(), Options{Scope: LayerScope(ra2.NewLayerStack(base, mod))})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "ai-reference", issues[0].Rule)
+	assert.Equal(t, "01000000-G", issues[0].Section)
+}
+
+func TestProjectOptions(t *testing.T) {
+	opts, err := ProjectOptions(project.Lint{
+		Disabled:   []string{"zero-cost"},
+		Severities: map[string]string{"unknown-key": "error"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zero-cost"}, opts.Disabled)
+	assert.Equal(t, map[string]Severity{"unknown-key": SeverityError}, opts.Severities)
+
+	_, err = ProjectOptions(project.Lint{Severities: map[string]string{"unknown-key": "fatal"}})
+	assert.EqualError(t, err, `lint rule unknown-key: unknown severity "fatal"`)
+}
+
+func TestSeverity_AtLeast(t *testing.T) {
+	assert.True(t, SeverityError.AtLeast(SeverityWarning))
+	assert.True(t, SeverityWarning.AtLeast(SeverityWarning))
+	assert.False(t, SeverityInfo.AtLeast(SeverityWarning))
+}
@@ -0,0 +1,196 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"ra2-ini-editor/internal/ra2"
+)
+
+// registries 是检查重复与缺失 section 的注册表。
+var registries = []ra2.SectionName{
+	ra2.SectionNameCountry,
+	ra2.SectionNameInfantry,
+	ra2.SectionNameVehicle,
+	ra2.SectionNameAircraft,
+	ra2.SectionNameBuilding,
+}
+
+// DefaultRules 返回内置的规则，调用方可以追加自己的规则后传给 Run。
+func DefaultRules() []Rule {
+	return []Rule{
+		{ID: "duplicate-id", Severity: SeverityError, Desc: "注册表中重复注册的名称", Check: checkDuplicateIDs},
+		{ID: "undefined-section", Severity: SeverityError, Desc: "已注册但没有定义的 section", Check: checkUndefinedSections},
+		{ID: "unregistered-section", Severity: SeverityWarning, Desc: "定义了单位属性但没有注册的 section", Check: checkUnregisteredSections},
+		{ID: "unknown-key", Severity: SeverityWarning, Desc: "schema 中没有的 key，可能是拼写错误", Check: checkUnknownKeys},
+		{ID: "invalid-value", Severity: SeverityError, Desc: "不符合 schema 类型的值", Check: checkInvalidValues},
+		{ID: "missing-uiname", Severity: SeverityWarning, Desc: "UIName 引用的标签在字符串表中不存在", Check: checkMissingUINames},
+		{ID: "zero-cost", Severity: SeverityWarning, Desc: "可以建造但价格为 0 的单位", Check: checkZeroCost},
+		{ID: "ares-key", Severity: SeverityError, Desc: "原版项目中使用了 Ares 等引擎扩展的 key", Check: checkEngineKeys},
+		{ID: "ai-reference", Severity: SeverityError, Desc: "AI 文件中无效的引用", Check: checkAI, Unscoped: true},
+		{ID: "sound-reference", Severity: SeverityWarning, Desc: "引用了未定义的音效或语音", Check: checkSounds},
+	}
+}
+
+func newIssue(section, key, format string, args ...any) Issue {
+	return Issue{Problem: ra2.Problem{Section: section, Key: key, Message: fmt.Sprintf(format, args...)}}
+}
+
+func checkDuplicateIDs(ctx *Context) []Issue {
+	var issues []Issue
+	for _, list := range registries {
+		seen := make(map[string]string)
+		for _, key := range ctx.Rules.Keys(string(list)) {
+			name, _ := ctx.Rules.Value(string(list), key)
+			if first, ok := seen[name]; ok {
+				issues = append(issues, newIssue(string(list), key, "%s is already registered as %s", name, first))
+				continue
+			}
+			seen[name] = key
+		}
+	}
+	return issues
+}
+
+func checkUndefinedSections(ctx *Context) []Issue {
+	var issues []Issue
+	for _, list := range registries {
+		for _, key := range ctx.Rules.Keys(string(list)) {
+			name, _ := ctx.Rules.Value(string(list), key)
+			// 合并后的规则会为注册的单位创建空 section，没有 key 同样视为未定义
+			if len(ctx.Rules.Keys(name)) == 0 {
+				issues = append(issues, newIssue(string(list), key, "%s is registered but not defined", name))
+			}
+		}
+	}
+	return issues
+}
+
+// technoKeys 是只有单位才会同时设置 Strength 的属性，用来识别没有注册的单位。
+var technoKeys = []string{"TechLevel", "Cost", "Owner", "Prerequisite"}
+
+func checkUnregisteredSections(ctx *Context) []Issue {
+	registered := make(map[string]bool)
+	for _, list := range registries {
+		for _, name := range ctx.Rules.Registered(list) {
+			registered[name] = true
+		}
+	}
+	var issues []Issue
+	for _, section := range ctx.Rules.Sections() {
+		if registered[section] {
+			continue
+		}
+		keys := ctx.Rules.Keys(section)
+		if slices.Contains(keys, "Strength") && slices.ContainsFunc(keys, func(k string) bool { return slices.Contains(technoKeys, k) }) {
+			issues = append(issues, newIssue(section, "", "section looks like a unit but is not registered in any type list"))
+		}
+	}
+	return issues
+}
+
+func checkUnknownKeys(ctx *Context) []Issue {
+	var issues []Issue
+	for _, unit := range ctx.Rules.Units() {
+		for _, key := range ctx.Rules.Keys(unit.Name) {
+			if _, ok := ctx.Schema.Flag(unit.Type, unit.Name, key); ok {
+				continue
+			}
+			// 引擎扩展的 key 由 ares-key 检查
+			if ctx.EngineSchema != nil {
+				if _, ok := ctx.EngineSchema.Flag(unit.Type, unit.Name, key); ok {
+					continue
+				}
+			}
+			issue := newIssue(unit.Name, key, "unknown key")
+			issue.Suggestions = ctx.Schema.Suggest(unit.Type, unit.Name, key)
+			if len(issue.Suggestions) > 0 {
+				issue.Message += ", did you mean " + strings.Join(issue.Suggestions, " or ") + "?"
+			}
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+func checkInvalidValues(ctx *Context) []Issue {
+	var issues []Issue
+	for _, unit := range ctx.Rules.Units() {
+		for _, key := range ctx.Rules.Keys(unit.Name) {
+			value, _ := ctx.Rules.Value(unit.Name, key)
+			if err := ctx.Schema.ValidateValue(unit.Type, unit.Name, key, value); err != nil {
+				issues = append(issues, newIssue(unit.Name, key, "%v", err))
+			}
+		}
+	}
+	return issues
+}
+
+func checkMissingUINames(ctx *Context) []Issue {
+	if ctx.Translation == nil {
+		return nil
+	}
+	var issues []Issue
+	for _, unit := range ctx.Rules.Units() {
+		label := strings.TrimSpace(unit.Get("UIName"))
+		if label == "" {
+			continue
+		}
+		if _, ok := ctx.Translation.Lookup(label); !ok {
+			issues = append(issues, newIssue(unit.Name, "UIName", "label %s is not in the string table", label))
+		}
+	}
+	return issues
+}
+
+func checkZeroCost(ctx *Context) []Issue {
+	var issues []Issue
+	for _, unit := range ctx.Rules.Units() {
+		// 未设置 TechLevel 时为 -1，不能建造
+		techLevel, err := strconv.Atoi(strings.TrimSpace(unit.Get("TechLevel")))
+		if err != nil || techLevel < 0 {
+			continue
+		}
+		cost, err := strconv.ParseFloat(strings.TrimSpace(unit.Get("Cost")), 64)
+		if err != nil || cost <= 0 {
+			issues = append(issues, newIssue(unit.Name, "Cost", "buildable at tech level %d but costs nothing", techLevel))
+		}
+	}
+	return issues
+}
+
+func checkEngineKeys(ctx *Context) []Issue {
+	if ctx.Engine != "" || ctx.EngineSchema == nil {
+		return nil
+	}
+	var issues []Issue
+	for _, unit := range ctx.Rules.Units() {
+		for _, key := range ctx.Rules.Keys(unit.Name) {
+			if flag, ok := ctx.EngineSchema.Flag(unit.Type, unit.Name, key); ok {
+				issues = append(issues, newIssue(unit.Name, key, "key requires %s but the project uses the original engine", flag.Engine))
+			}
+		}
+	}
+	return issues
+}
+
+func checkAI(ctx *Context) []Issue {
+	if ctx.AI == nil {
+		return nil
+	}
+	return fromProblems(ctx.AI.Validate(ctx.Rules))
+}
+
+func checkSounds(ctx *Context) []Issue {
+	return fromProblems(ra2.ValidateSounds(ctx.Rules, ctx.Schema, ctx.Sounds, ctx.EVA))
+}
+
+func fromProblems(problems []ra2.Problem) []Issue {
+	var issues []Issue
+	for _, p := range problems {
+		issues = append(issues, Issue{Problem: p})
+	}
+	return issues
+}
@@ -0,0 +1,72 @@
+package mix
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"math/big"
+	"slices"
+
+	"golang.org/x/crypto/blowfish"
+)
+
+// westwoodPublicKey 是 Westwood 用来加密 MIX 头部 Blowfish 密钥的 RSA 公钥
+const westwoodPublicKey = "AihRvNoIbTn85FZRYNZRcT+i6KpU+maCsEqr3Q5q+LDB5tH7Tz2qQ38V"
+
+const (
+	keySourceSize   = 80
+	blowfishKeySize = 56
+)
+
+// deriveKey 从头部的 80 字节密钥源计算 Blowfish 密钥，测试中可以替换。
+var deriveKey = blowfishKey
+
+func blowfishKey(src []byte) []byte {
+	der, _ := base64.StdEncoding.DecodeString(westwoodPublicKey)
+	// der[0] 为 INTEGER 标签，der[1] 为长度，之后是大端序的模数
+	n := new(big.Int).SetBytes(der[2 : 2+int(der[1])])
+	e := big.NewInt(0x10001)
+
+	// 每 a+1 字节的小端序输入块解密为 a 字节的输出
+	a := (n.BitLen() - 2) / 8
+	key := make([]byte, 0, len(src))
+	for len(src) >= a+1 {
+		m := new(big.Int).SetBytes(reversed(src[:a+1]))
+		c := new(big.Int).Exp(m, e, n)
+		out := reversed(c.FillBytes(make([]byte, a+1)))
+		key = append(key, out[:a]...)
+		src = src[a+1:]
+	}
+	return key[:blowfishKeySize]
+}
+
+// decryptBlocks 原地解密 buf，buf 长度必须是 8 的倍数。
+// Westwood 的 Blowfish 以小端序读取 32 位字，与标准实现相反。
+func decryptBlocks(c *blowfish.Cipher, buf []byte) {
+	for i := 0; i+blowfish.BlockSize <= len(buf); i += blowfish.BlockSize {
+		block := buf[i : i+blowfish.BlockSize]
+		swapWords(block)
+		c.Decrypt(block, block)
+		swapWords(block)
+	}
+}
+
+// encryptBlocks 是 decryptBlocks 的逆操作。
+func encryptBlocks(c *blowfish.Cipher, buf []byte) {
+	for i := 0; i+blowfish.BlockSize <= len(buf); i += blowfish.BlockSize {
+		block := buf[i : i+blowfish.BlockSize]
+		swapWords(block)
+		c.Encrypt(block, block)
+		swapWords(block)
+	}
+}
+
+func swapWords(block []byte) {
+	binary.BigEndian.PutUint32(block[0:], binary.LittleEndian.Uint32(block[0:]))
+	binary.BigEndian.PutUint32(block[4:], binary.LittleEndian.Uint32(block[4:]))
+}
+
+func reversed(b []byte) []byte {
+	r := slices.Clone(b)
+	slices.Reverse(r)
+	return r
+}
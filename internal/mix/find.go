@@ -0,0 +1,145 @@
+package mix
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// nestedNames 是游戏 MIX 中常见的嵌套 MIX，文件名数据库中没有记录时按这些名称查找
+var nestedNames = []string{
+	"localmd.mix",
+	"cachemd.mix",
+	"local.mix",
+	"cache.mix",
+}
+
+// searchOrder 返回游戏加载顶层 MIX 的顺序，靠前的优先。
+func searchOrder() []string {
+	var names []string
+	for i := 99; i >= 1; i-- {
+		names = append(names, fmt.Sprintf("expandmd%02d.mix", i))
+	}
+	return append(names, "ra2md.mix", "langmd.mix", "ra2.mix", "language.mix")
+}
+
+// Find 按游戏的加载顺序在 dir 的 MIX 文件（包括嵌套的 MIX）中查找 name，
+// 返回可以交给 OpenPath 的路径，如 dir/ra2md.mix/localmd.mix/rulesmd.ini。
+// 找不到时返回空。
+func Find(dir, name string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	files := make(map[string]string)
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			files[strings.ToLower(entry.Name())] = entry.Name()
+		}
+	}
+
+	for _, archiveName := range searchOrder() {
+		filename, ok := files[archiveName]
+		if !ok {
+			continue
+		}
+		a, err := Open(filepath.Join(dir, filename))
+		if err != nil {
+			return "", err
+		}
+		path := findIn(a, filepath.Join(dir, filename), name)
+		a.Close()
+		if path != "" {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+func findIn(a *Archive, path, name string) string {
+	if a.Has(name) {
+		return filepath.Join(path, name)
+	}
+	candidates := append([]string(nil), nestedNames...)
+	for _, n := range a.Names() {
+		if strings.HasSuffix(strings.ToLower(n), ".mix") {
+			candidates = append(candidates, n)
+		}
+	}
+	for _, nestedName := range candidates {
+		if !a.Has(nestedName) {
+			continue
+		}
+		nested, err := a.OpenMix(nestedName)
+		if err != nil {
+			continue
+		}
+		if found := findIn(nested, filepath.Join(path, nestedName), name); found != "" {
+			return found
+		}
+	}
+	return ""
+}
+
+// OpenPath 打开磁盘上的文件，或 MIX 文件中的文件。path 中间可以包含 MIX 文件，
+// 如 ra2md.mix/localmd.mix/rulesmd.ini。
+func OpenPath(path string) (io.ReadCloser, error) {
+	var inner []string
+	outer := filepath.Clean(path)
+	for {
+		info, err := os.Stat(outer)
+		if err == nil {
+			if info.IsDir() {
+				return nil, errors.Wrap(os.ErrNotExist, path)
+			}
+			break
+		}
+		parent := filepath.Dir(outer)
+		if parent == outer {
+			return nil, errors.Wrap(os.ErrNotExist, path)
+		}
+		inner = append([]string{filepath.Base(outer)}, inner...)
+		outer = parent
+	}
+
+	if len(inner) == 0 {
+		f, err := os.Open(outer)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return f, nil
+	}
+
+	a, err := Open(outer)
+	if err != nil {
+		return nil, err
+	}
+	current := a
+	for _, name := range inner[:len(inner)-1] {
+		nested, err := current.OpenMix(name)
+		if err != nil {
+			a.Close()
+			return nil, err
+		}
+		current = nested
+	}
+	f, err := current.File(inner[len(inner)-1])
+	if err != nil {
+		a.Close()
+		return nil, err
+	}
+	return &archiveFile{SectionReader: f, closer: a}, nil
+}
+
+type archiveFile struct {
+	*io.SectionReader
+	closer io.Closer
+}
+
+func (f *archiveFile) Close() error {
+	return f.closer.Close()
+}
@@ -0,0 +1,42 @@
+package mix
+
+import (
+	"hash/crc32"
+	"strings"
+)
+
+// ID 计算 TS/RA2 格式 MIX 中文件名的 ID，即补齐后文件名的 CRC32。
+func ID(name string) uint32 {
+	b := []byte(normalizeName(name))
+	l := len(b)
+	a := l >> 2
+	if l&3 != 0 {
+		b = append(b, byte(l-(a<<2)))
+		for i := 3 - (l & 3); i > 0; i-- {
+			b = append(b, b[a<<2])
+		}
+	}
+	return crc32.ChecksumIEEE(b)
+}
+
+// ClassicID 计算 TD/RA 格式 MIX 中文件名的 ID。
+func ClassicID(name string) uint32 {
+	b := []byte(normalizeName(name))
+	var id uint32
+	for i := 0; i < len(b); {
+		var a uint32
+		for j := 0; j < 4; j++ {
+			a >>= 8
+			if i < len(b) {
+				a += uint32(b[i]) << 24
+			}
+			i++
+		}
+		id = (id<<1 | id>>31) + a
+	}
+	return id
+}
+
+func normalizeName(name string) string {
+	return strings.ReplaceAll(strings.ToUpper(name), "/", "\\")
+}
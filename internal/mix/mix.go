@@ -0,0 +1,276 @@
+package mix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blowfish"
+)
+
+const (
+	flagChecksum  = 0x00010000 // 文件末尾附带 20 字节 SHA1
+	flagEncrypted = 0x00020000 // 头部使用 Blowfish 加密
+
+	headerSize = 6  // 文件数 uint16 + 数据区大小 uint32
+	entrySize  = 12 // ID uint32 + 偏移 uint32 + 大小 uint32
+)
+
+// Entry 是 MIX 文件索引中的一项，Offset 相对于数据区起始位置。
+type Entry struct {
+	ID     uint32
+	Offset uint32
+	Size   uint32
+}
+
+// Archive 是一个只读的 MIX 文件，支持 TD/RA 的旧格式与 TS/RA2 的新格式（含加密头部）。
+type Archive struct {
+	r       io.ReaderAt
+	closer  io.Closer
+	body    int64
+	entries []Entry
+	index   map[uint32]Entry
+	names   map[uint32]string // 来自 local mix database.dat
+
+	Classic   bool // TD/RA 旧格式，文件名使用 ClassicID
+	Encrypted bool
+}
+
+// Open 打开磁盘上的 MIX 文件，使用完毕后需要调用 Close。
+func Open(filename string) (*Archive, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	a, err := New(f)
+	if err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, filename)
+	}
+	a.closer = f
+	return a, nil
+}
+
+// New 从 r 中读取 MIX 文件的索引。
+func New(r io.ReaderAt) (*Archive, error) {
+	var head [4]byte
+	if _, err := r.ReadAt(head[:], 0); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	a := &Archive{r: r}
+	var (
+		header []byte
+		err    error
+	)
+	switch flags := binary.LittleEndian.Uint32(head[:]); {
+	case flags&0xFFFF != 0:
+		// 旧格式没有 flags，开头直接是文件数
+		a.Classic = true
+		header, a.body, err = readPlainHeader(r, 0)
+	case flags&flagEncrypted != 0:
+		a.Encrypted = true
+		header, a.body, err = readEncryptedHeader(r, 4)
+	default:
+		header, a.body, err = readPlainHeader(r, 4)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	count := int(binary.LittleEndian.Uint16(header[0:]))
+	a.entries = make([]Entry, count)
+	a.index = make(map[uint32]Entry, count)
+	for i := range a.entries {
+		b := header[headerSize+i*entrySize:]
+		entry := Entry{
+			ID:     binary.LittleEndian.Uint32(b[0:]),
+			Offset: binary.LittleEndian.Uint32(b[4:]),
+			Size:   binary.LittleEndian.Uint32(b[8:]),
+		}
+		a.entries[i] = entry
+		a.index[entry.ID] = entry
+	}
+
+	if err := a.readDatabase(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// readPlainHeader 读取未加密的头部，返回头部内容与数据区起始位置。
+func readPlainHeader(r io.ReaderAt, offset int64) ([]byte, int64, error) {
+	var head [headerSize]byte
+	if _, err := r.ReadAt(head[:], offset); err != nil {
+		return nil, 0, errors.WithStack(err)
+	}
+	count := int64(binary.LittleEndian.Uint16(head[0:]))
+	header := make([]byte, headerSize+count*entrySize)
+	if _, err := r.ReadAt(header, offset); err != nil {
+		return nil, 0, errors.Wrap(err, "read mix index")
+	}
+	return header, offset + int64(len(header)), nil
+}
+
+// readEncryptedHeader 读取 80 字节密钥源之后的加密头部。
+// 加密头部按 8 字节对齐，数据区紧随其后。
+func readEncryptedHeader(r io.ReaderAt, offset int64) ([]byte, int64, error) {
+	keySource := make([]byte, keySourceSize)
+	if _, err := r.ReadAt(keySource, offset); err != nil {
+		return nil, 0, errors.WithStack(err)
+	}
+	c, err := blowfish.NewCipher(deriveKey(keySource))
+	if err != nil {
+		return nil, 0, errors.WithStack(err)
+	}
+	offset += keySourceSize
+
+	first := make([]byte, blowfish.BlockSize)
+	if _, err := r.ReadAt(first, offset); err != nil {
+		return nil, 0, errors.WithStack(err)
+	}
+	decryptBlocks(c, first)
+	count := int(binary.LittleEndian.Uint16(first[0:]))
+	size := headerSize + count*entrySize
+	size = (size + blowfish.BlockSize - 1) / blowfish.BlockSize * blowfish.BlockSize
+
+	header := make([]byte, size)
+	if _, err := r.ReadAt(header, offset); err != nil {
+		return nil, 0, errors.Wrap(err, "read mix index")
+	}
+	decryptBlocks(c, header)
+	return header, offset + int64(size), nil
+}
+
+// Close 关闭由 Open 打开的文件。
+func (a *Archive) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+// Entries 按索引中的顺序返回所有文件。
+func (a *Archive) Entries() []Entry {
+	return append([]Entry(nil), a.entries...)
+}
+
+// Names 返回 local mix database.dat 中记录的文件名，没有记录时为空。
+func (a *Archive) Names() []string {
+	names := make([]string, 0, len(a.names))
+	for _, name := range a.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Name 返回 id 对应的文件名，未知时返回 false。
+func (a *Archive) Name(id uint32) (string, bool) {
+	name, ok := a.names[id]
+	return name, ok
+}
+
+func (a *Archive) lookup(name string) (Entry, bool) {
+	id := ID(name)
+	if a.Classic {
+		id = ClassicID(name)
+	}
+	entry, ok := a.index[id]
+	return entry, ok
+}
+
+func (a *Archive) Has(name string) bool {
+	_, ok := a.lookup(name)
+	return ok
+}
+
+// File 返回 name 的内容，不存在时返回 os.ErrNotExist。
+func (a *Archive) File(name string) (*io.SectionReader, error) {
+	entry, ok := a.lookup(name)
+	if !ok {
+		return nil, errors.Wrap(os.ErrNotExist, name)
+	}
+	return a.section(entry), nil
+}
+
+func (a *Archive) ReadFile(name string) ([]byte, error) {
+	f, err := a.File(name)
+	if err != nil {
+		return nil, err
+	}
+	bts, err := io.ReadAll(f)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return bts, nil
+}
+
+// OpenMix 打开嵌套在当前文件中的 MIX 文件。
+func (a *Archive) OpenMix(name string) (*Archive, error) {
+	f, err := a.File(name)
+	if err != nil {
+		return nil, err
+	}
+	nested, err := New(f)
+	if err != nil {
+		return nil, errors.Wrap(err, name)
+	}
+	return nested, nil
+}
+
+func (a *Archive) section(entry Entry) *io.SectionReader {
+	return io.NewSectionReader(a.r, a.body+int64(entry.Offset), int64(entry.Size))
+}
+
+func (a *Archive) readDatabase() error {
+	entry, ok := a.lookup(DatabaseName)
+	if !ok {
+		return nil
+	}
+	bts, err := io.ReadAll(a.section(entry))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	names, err := parseDatabase(bts)
+	if err != nil {
+		// 数据库损坏不影响读取文件
+		return nil
+	}
+	a.names = make(map[uint32]string, len(names))
+	for _, name := range names {
+		id := ID(name)
+		if a.Classic {
+			id = ClassicID(name)
+		}
+		a.names[id] = name
+	}
+	return nil
+}
+
+// DatabaseName 是 MIX 中记录文件名列表的文件
+const DatabaseName = "local mix database.dat"
+
+// databaseMagic 是 XCC 文件头的标识，共 32 字节
+const databaseMagic = "XCC by Olaf van der Spek\x1a\x04\x17\x27\x10\x19\x80\x00"
+
+const (
+	databaseHeaderSize = 52 // 标识 32 + 大小 4 + 类型 4 + 版本 4 + 游戏 4 + 文件数 4
+	databaseGameRA2    = 5
+)
+
+func parseDatabase(bts []byte) ([]string, error) {
+	if len(bts) < databaseHeaderSize || string(bts[:len(databaseMagic)]) != databaseMagic {
+		return nil, errors.New("invalid local mix database")
+	}
+	count := int(binary.LittleEndian.Uint32(bts[48:]))
+	names := strings.Split(string(bytes.TrimRight(bts[databaseHeaderSize:], "\x00")), "\x00")
+	if len(names) < count {
+		return nil, errors.New("truncated local mix database")
+	}
+	return names[:count], nil
+}
@@ -0,0 +1,188 @@
+package mix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blowfish"
+)
+
+type testFile struct {
+	name string
+	data []byte
+}
+
+// buildTestMix 按指定格式生成 MIX 文件，encryptKey 不为空时加密头部。
+func buildTestMix(t testing.TB, classic bool, encryptKey []byte, files ...testFile) []byte {
+	var index, body bytes.Buffer
+	for _, f := range files {
+		id := ID(f.name)
+		if classic {
+			id = ClassicID(f.name)
+		}
+		binary.Write(&index, binary.LittleEndian, []uint32{id, uint32(body.Len()), uint32(len(f.data))})
+		body.Write(f.data)
+	}
+	var header bytes.Buffer
+	binary.Write(&header, binary.LittleEndian, uint16(len(files)))
+	binary.Write(&header, binary.LittleEndian, uint32(body.Len()))
+	header.Write(index.Bytes())
+
+	var buf bytes.Buffer
+	switch {
+	case classic:
+		buf.Write(header.Bytes())
+	case encryptKey != nil:
+		binary.Write(&buf, binary.LittleEndian, uint32(flagEncrypted))
+		buf.Write(make([]byte, keySourceSize))
+		for header.Len()%blowfish.BlockSize != 0 {
+			header.WriteByte(0)
+		}
+		c, err := blowfish.NewCipher(encryptKey)
+		assert.NoError(t, err)
+		encrypted := header.Bytes()
+		encryptBlocks(c, encrypted)
+		buf.Write(encrypted)
+	default:
+		binary.Write(&buf, binary.LittleEndian, uint32(0))
+		buf.Write(header.Bytes())
+	}
+	buf.Write(body.Bytes())
+	return buf.Bytes()
+}
+
+func TestID(t *testing.T) {
+	// XCC 中 local mix database.dat 的 ID
+	assert.Equal(t, uint32(0x366E051F), ID(DatabaseName))
+	assert.Equal(t, uint32(0x54C2D545), ClassicID(DatabaseName))
+	assert.Equal(t, ID("RULESMD.INI"), ID("rulesmd.ini"))
+}
+
+func TestNew(t *testing.T) {
+	files := []testFile{
+		{name: "rulesmd.ini", data: []byte("[General]\nName=Rules\n")},
+		{name: "artmd.ini", data: []byte("[HTNK]\nVoxel=yes\n")},
+	}
+	key := bytes.Repeat([]byte{0x5a}, blowfishKeySize)
+
+	tests := []struct {
+		name        string
+		data        []byte
+		wantClassic bool
+		wantCrypt   bool
+	}{
+		{name: "classic", data: buildTestMix(t, true, nil, files...), wantClassic: true},
+		{name: "ra2", data: buildTestMix(t, false, nil, files...)},
+		{name: "encrypted", data: buildTestMix(t, false, key, files...), wantCrypt: true},
+	}
+	deriveKey = func([]byte) []byte { return key }
+	defer func() { deriveKey = blowfishKey }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := New(bytes.NewReader(tt.data))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantClassic, a.Classic)
+			assert.Equal(t, tt.wantCrypt, a.Encrypted)
+			assert.Len(t, a.Entries(), 2)
+			for _, f := range files {
+				got, err := a.ReadFile(f.name)
+				assert.NoError(t, err)
+				assert.Equal(t, f.data, got)
+			}
+			_, err = a.ReadFile("aimd.ini")
+			assert.ErrorIs(t, err, os.ErrNotExist)
+		})
+	}
+}
+
+func TestBlowfishKey(t *testing.T) {
+	key := blowfishKey(make([]byte, keySourceSize))
+	assert.Len(t, key, blowfishKeySize)
+	assert.Equal(t, make([]byte, blowfishKeySize), key, "0^e mod n is 0")
+
+	// 回归用例：期望值按同一公式独立计算，只能发现实现的改动，不能证明公式与游戏一致，
+	// 与游戏一致性由 TestBlowfishKey_Game 用真实的加密 MIX 验证
+	src, err := hex.DecodeString("0b30557a9fc4e90e33587da2c7ec11365b80a5caef14395e83a8cdf2173c6186abd0f51a3f6489ae" +
+		"d3f81d42678cb1d6fb20456a8fb4d9fe23486d92b7dc01264b7095badf04294e7398bde2072c5176")
+	assert.NoError(t, err)
+	want, err := hex.DecodeString("cff1b39d157021b3b31610d612779a86617c7c3f8771c9ff3f05b1a540d7df68" +
+		"05e79b15204545aa223ac446040655c723253f1cb08eb926")
+	assert.NoError(t, err)
+	assert.Equal(t, want, blowfishKey(src))
+}
+
+// TestBlowfishKey_Game 用游戏目录中的 ra2md.mix 验证密钥推导，
+// 目录由环境变量 RA2_DIR 指定，未设置时跳过。
+func TestBlowfishKey_Game(t *testing.T) {
+	dir := os.Getenv("RA2_DIR")
+	if dir == "" {
+		t.Skip("RA2_DIR not set")
+	}
+	entries, err := os.ReadDir(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	path := ""
+	for _, entry := range entries {
+		if strings.EqualFold(entry.Name(), "ra2md.mix") {
+			path = filepath.Join(dir, entry.Name())
+		}
+	}
+	if path == "" {
+		t.Skip("ra2md.mix not found in RA2_DIR")
+	}
+	a, err := Open(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer a.Close()
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+
+	// 密钥推导错误时解出的索引是随机数据，文件数、偏移与大小不可能都落在文件范围内
+	assert.True(t, a.Encrypted)
+	assert.NotEmpty(t, a.Entries())
+	for _, entry := range a.Entries() {
+		assert.LessOrEqual(t, a.body+int64(entry.Offset)+int64(entry.Size), info.Size(), "entry %08X", entry.ID)
+	}
+	assert.True(t, a.Has("local.mix") || a.Has("localmd.mix"))
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	localmd := buildTestMix(t, false, nil, testFile{name: "rulesmd.ini", data: []byte("[General]\n")})
+	ra2md := buildTestMix(t, false, nil, testFile{name: "localmd.mix", data: localmd})
+	langmd := buildTestMix(t, false, nil, testFile{name: "ra2md.csf", data: []byte(" FSC")})
+	expand := buildTestMix(t, false, nil, testFile{name: "ra2md.csf", data: []byte(" FSC patched")})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "RA2MD.MIX"), ra2md, 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "langmd.mix"), langmd, 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "expandmd01.mix"), expand, 0o644))
+
+	path, err := Find(dir, "rulesmd.ini")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "RA2MD.MIX", "localmd.mix", "rulesmd.ini"), path)
+	f, err := OpenPath(path)
+	assert.NoError(t, err)
+	bts := make([]byte, 10)
+	n, _ := f.Read(bts)
+	assert.Equal(t, "[General]\n", string(bts[:n]))
+	assert.NoError(t, f.Close())
+
+	path, err = Find(dir, "ra2md.csf")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "expandmd01.mix", "ra2md.csf"), path)
+
+	path, err = Find(dir, "aimd.ini")
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+
+	_, err = OpenPath(filepath.Join(dir, "langmd.mix", "missing.ini"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,94 @@
+package mix
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"io"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+// Writer 生成未加密的 TS/RA2 格式 MIX 文件，并附带记录文件名的 local mix database.dat。
+type Writer struct {
+	names []string
+	files map[string][]byte
+}
+
+func NewWriter() *Writer {
+	return &Writer{
+		files: make(map[string][]byte),
+	}
+}
+
+// Add 添加文件，同名文件会被替换。
+func (w *Writer) Add(name string, data []byte) error {
+	if _, ok := w.files[name]; !ok {
+		if slices.ContainsFunc(w.names, func(n string) bool { return ID(n) == ID(name) }) {
+			return errors.Errorf("file name %s conflicts with an existing file", name)
+		}
+		w.names = append(w.names, name)
+	}
+	w.files[name] = data
+	return nil
+}
+
+func (w *Writer) WriteTo(out io.Writer) (int64, error) {
+	type file struct {
+		id   uint32
+		data []byte
+	}
+	files := make([]file, 0, len(w.names)+1)
+	for _, name := range w.names {
+		files = append(files, file{id: ID(name), data: w.files[name]})
+	}
+	files = append(files, file{id: ID(DatabaseName), data: buildDatabase(w.names)})
+	// 游戏按有符号 ID 二分查找索引
+	slices.SortFunc(files, func(a, b file) int {
+		return cmp.Compare(int32(a.id), int32(b.id))
+	})
+
+	var buf bytes.Buffer
+	var bodySize uint32
+	for _, f := range files {
+		bodySize += uint32(len(f.data))
+	}
+	binary.Write(&buf, binary.LittleEndian, uint32(0))
+	binary.Write(&buf, binary.LittleEndian, uint16(len(files)))
+	binary.Write(&buf, binary.LittleEndian, bodySize)
+	var offset uint32
+	for _, f := range files {
+		binary.Write(&buf, binary.LittleEndian, []uint32{f.id, offset, uint32(len(f.data))})
+		offset += uint32(len(f.data))
+	}
+	for _, f := range files {
+		buf.Write(f.data)
+	}
+
+	n, err := out.Write(buf.Bytes())
+	if err != nil {
+		return int64(n), errors.WithStack(err)
+	}
+	return int64(n), nil
+}
+
+func buildDatabase(names []string) []byte {
+	var body bytes.Buffer
+	for _, name := range names {
+		body.WriteString(name)
+		body.WriteByte(0)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(databaseMagic)
+	binary.Write(&buf, binary.LittleEndian, []uint32{
+		uint32(databaseHeaderSize + body.Len()),
+		0, // 类型：local mix database
+		0, // 版本
+		databaseGameRA2,
+		uint32(len(names)),
+	})
+	buf.Write(body.Bytes())
+	return buf.Bytes()
+}
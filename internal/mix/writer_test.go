@@ -0,0 +1,39 @@
+package mix
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter(t *testing.T) {
+	w := NewWriter()
+	assert.NoError(t, w.Add("rulesmd.ini", []byte("[General]\n")))
+	assert.NoError(t, w.Add("artmd.ini", []byte("[HTNK]\n")))
+	assert.NoError(t, w.Add("ra2md.csf", []byte(" FSC")))
+	assert.NoError(t, w.Add("rulesmd.ini", []byte("[General]\nName=Mod\n")))
+
+	var buf bytes.Buffer
+	_, err := w.WriteTo(&buf)
+	assert.NoError(t, err)
+
+	a, err := New(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.False(t, a.Classic)
+	assert.False(t, a.Encrypted)
+	assert.Len(t, a.Entries(), 4)
+	assert.Equal(t, []string{"artmd.ini", "ra2md.csf", "rulesmd.ini"}, a.Names())
+
+	got, err := a.ReadFile("rulesmd.ini")
+	assert.NoError(t, err)
+	assert.Equal(t, "[General]\nName=Mod\n", string(got))
+	got, err = a.ReadFile("ARTMD.INI")
+	assert.NoError(t, err)
+	assert.Equal(t, "[HTNK]\n", string(got))
+
+	entries := a.Entries()
+	for i := 1; i < len(entries); i++ {
+		assert.Less(t, int32(entries[i-1].ID), int32(entries[i].ID))
+	}
+}
@@ -0,0 +1,183 @@
+package project
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"ra2-ini-editor/internal/fileutil"
+)
+
+// Version 是当前的项目文件格式版本
+const Version = 1
+
+// Project 描述一个 mod 项目的基础数据、覆盖层、翻译与 schema 设置，
+// 团队成员共享同一个项目文件即可获得相同的编辑环境。
+// 项目文件中的相对路径都相对于项目文件所在目录。
+type Project struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+
+	GameDir      string   `json:"game_dir,omitempty"` // 游戏安装或解包目录，Base 中未指定的文件从这里查找
+	Base         Base     `json:"base"`
+	IncludeRoots []string `json:"include_roots,omitempty"` // 查找相对路径文件的额外目录
+	Layers       []Layer  `json:"layers"`                  // 按从底到顶的顺序排列
+
+	Package Package `json:"package"`
+
+	Language         string   `json:"language"`                    // 翻译使用的语言，如 zh-TW
+	Schema           string   `json:"schema,omitempty"`            // 为空时使用内置 schema
+	SchemaExtensions []string `json:"schema_extensions,omitempty"` // 追加到 schema 的 flag 定义
+	Engine           string   `json:"engine,omitempty"`            // 使用的引擎扩展，为空表示原版
+
+	Lint Lint `json:"lint"`
+
+	dir string
+}
+
+// EngineAres 表示项目使用 Ares 引擎扩展，加载 schema 时会追加内置的 Ares flag。
+const EngineAres = "ares"
+
+// Lint 是项目的 lint 设置，规则 ID 见 lint 包。
+type Lint struct {
+	Disabled   []string          `json:"disabled,omitempty"`   // 关闭的规则
+	Severities map[string]string `json:"severities,omitempty"` // 按规则覆盖默认的级别：error、warning 或 info
+}
+
+// Base 是游戏的基础数据文件，为空时从 GameDir 中查找，仍找不到时使用内置的数据。
+type Base struct {
+	Rules string `json:"rules,omitempty"`
+	Art   string `json:"art,omitempty"`
+	AI    string `json:"ai,omitempty"`
+	Sound string `json:"sound,omitempty"`
+	EVA   string `json:"eva,omitempty"`
+	CSF   string `json:"csf,omitempty"`
+}
+
+// Package 描述打包 mod 时除规则外还包含的文件。
+type Package struct {
+	Output string   `json:"output,omitempty"` // 默认为 expandmd99.mix
+	Art    string   `json:"art,omitempty"`    // art 覆盖文件，与基础 art 合并后打包为 artmd.ini
+	CSF    string   `json:"csf,omitempty"`    // 打包为 ra2md.csf
+	Files  []string `json:"files,omitempty"`  // 其他原样打包的文件
+}
+
+type Layer struct {
+	Name    string `json:"name"`
+	Path    string `json:"path,omitempty"` // 为空表示未保存的空层
+	Enabled bool   `json:"enabled"`
+	Target  bool   `json:"target,omitempty"` // 是否为写入目标层
+}
+
+// Default 返回未打开项目时使用的默认设置：内置数据加一个空的用户层。
+func Default() *Project {
+	return &Project{
+		Version:  Version,
+		Language: "zh-TW",
+		Layers: []Layer{
+			{Name: "user", Enabled: true, Target: true},
+		},
+	}
+}
+
+func Load(filename string) (*Project, error) {
+	bts, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var p Project
+	if err := json.Unmarshal(bts, &p); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if p.Version > Version {
+		return nil, errors.Errorf("unsupported project version %d", p.Version)
+	}
+	p.dir = filepath.Dir(filename)
+	return &p, nil
+}
+
+// Save 保存到 filename，之后的相对路径都相对于 filename 所在目录。
+func (p *Project) Save(filename string) error {
+	dir := filepath.Dir(filename)
+	saved := *p
+	saved.Version = Version
+	saved.Base = Base{
+		Rules: p.relativeTo(dir, p.Base.Rules),
+		Art:   p.relativeTo(dir, p.Base.Art),
+		AI:    p.relativeTo(dir, p.Base.AI),
+		Sound: p.relativeTo(dir, p.Base.Sound),
+		EVA:   p.relativeTo(dir, p.Base.EVA),
+		CSF:   p.relativeTo(dir, p.Base.CSF),
+	}
+	saved.GameDir = p.relativeTo(dir, p.GameDir)
+	saved.Schema = p.relativeTo(dir, p.Schema)
+	saved.IncludeRoots = make([]string, len(p.IncludeRoots))
+	for i, root := range p.IncludeRoots {
+		saved.IncludeRoots[i] = p.relativeTo(dir, root)
+	}
+	saved.SchemaExtensions = make([]string, len(p.SchemaExtensions))
+	for i, ext := range p.SchemaExtensions {
+		saved.SchemaExtensions[i] = p.relativeTo(dir, ext)
+	}
+	saved.Package = Package{
+		Output: p.relativeTo(dir, p.Package.Output),
+		Art:    p.relativeTo(dir, p.Package.Art),
+		CSF:    p.relativeTo(dir, p.Package.CSF),
+		Files:  make([]string, len(p.Package.Files)),
+	}
+	for i, file := range p.Package.Files {
+		saved.Package.Files[i] = p.relativeTo(dir, file)
+	}
+	saved.Layers = make([]Layer, len(p.Layers))
+	for i, layer := range p.Layers {
+		layer.Path = p.relativeTo(dir, layer.Path)
+		saved.Layers[i] = layer
+	}
+
+	bts, err := json.MarshalIndent(&saved, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := fileutil.WriteFileAtomic(filename, bts, false); err != nil {
+		return errors.WithStack(err)
+	}
+	*p = saved
+	p.dir = dir
+	return nil
+}
+
+// Resolve 返回 path 的绝对路径。相对路径先在项目目录下查找，
+// 找不到时依次在 IncludeRoots 中查找。path 为空时返回空。
+func (p *Project) Resolve(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	candidate := filepath.Join(p.dir, path)
+	if _, err := os.Stat(candidate); err == nil {
+		return candidate
+	}
+	for _, root := range p.IncludeRoots {
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(p.dir, root)
+		}
+		if _, err := os.Stat(filepath.Join(root, path)); err == nil {
+			return filepath.Join(root, path)
+		}
+	}
+	return candidate
+}
+
+// relativeTo 将 path 转为相对于 dir 的路径，无法转换时保留绝对路径。
+func (p *Project) relativeTo(dir, path string) string {
+	path = p.Resolve(path)
+	if path == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
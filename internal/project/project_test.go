@@ -0,0 +1,46 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProject_SaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "mod", "shared"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "mod", "shared", "patch.ini"), nil, 0o644))
+
+	p := Default()
+	p.Name = "test"
+	p.Base.Rules = filepath.Join(dir, "game", "rulesmd.ini")
+	p.Layers = []Layer{
+		{Name: "mod", Path: filepath.Join(dir, "mod", "rules.ini"), Enabled: true, Target: true},
+		{Name: "scratch", Enabled: false},
+	}
+	filename := filepath.Join(dir, "mod", "project.json")
+	assert.NoError(t, p.Save(filename))
+	assert.Equal(t, "../game/rulesmd.ini", p.Base.Rules)
+	assert.Equal(t, "rules.ini", p.Layers[0].Path)
+
+	loaded, err := Load(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", loaded.Name)
+	assert.Equal(t, "zh-TW", loaded.Language)
+	assert.Equal(t, p.Layers, loaded.Layers)
+	assert.Equal(t, filepath.Join(dir, "game", "rulesmd.ini"), loaded.Resolve(loaded.Base.Rules))
+	assert.Equal(t, "", loaded.Resolve(loaded.Base.Art))
+
+	loaded.IncludeRoots = []string{"shared"}
+	assert.Equal(t, filepath.Join(dir, "mod", "shared", "patch.ini"), loaded.Resolve("patch.ini"))
+}
+
+func TestLoad_UnsupportedVersion(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "project.json")
+	assert.NoError(t, os.WriteFile(filename, []byte(`{"version": 99}`), 0o644))
+
+	_, err := Load(filename)
+	assert.Error(t, err)
+}
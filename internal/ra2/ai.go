@@ -0,0 +1,543 @@
+package ra2
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"gopkg.in/ini.v1"
+)
+
+// aimd.ini 中的注册表
+const (
+	SectionNameTaskForces     SectionName = "TaskForces"
+	SectionNameScriptTypes    SectionName = "ScriptTypes"
+	SectionNameTeamTypes      SectionName = "TeamTypes"
+	SectionNameAITriggerTypes SectionName = "AITriggerTypes"
+)
+
+// NoneValue 表示未引用任何对象，如 AITriggerType 的第二个小队。
+const NoneValue = "<none>"
+
+// aiTriggerFields 是 AITriggerTypes 中每条记录的字段数
+const aiTriggerFields = 18
+
+type TaskForceMember struct {
+	Count int    `json:"count"`
+	Type  string `json:"type"` // 单位的注册名，如 E1
+}
+
+// TaskForce 是 AI 小队的兵力构成。
+type TaskForce struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Group   int               `json:"group"`
+	Members []TaskForceMember `json:"members"`
+}
+
+type ScriptAction struct {
+	Action   int `json:"action"`
+	Argument int `json:"argument"`
+}
+
+// ScriptType 是 AI 小队依次执行的脚本动作。
+type ScriptType struct {
+	ID      string         `json:"id"`
+	Name    string         `json:"name"`
+	Actions []ScriptAction `json:"actions"`
+}
+
+// TeamType 将兵力与脚本组合为一支小队，其余设置保存在 Properties 中。
+type TeamType struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	TaskForce  string     `json:"task_force"`
+	Script     string     `json:"script"`
+	Properties []Property `json:"properties"`
+}
+
+// AITriggerType 描述 AI 在什么条件下组建哪些小队。
+type AITriggerType struct {
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	Team1           string  `json:"team1"`
+	House           string  `json:"house"` // <all> 表示所有国家
+	TechLevel       int     `json:"tech_level"`
+	ConditionType   int     `json:"condition_type"` // -1 表示无条件
+	ConditionObject string  `json:"condition_object"`
+	Comparator      string  `json:"comparator"`
+	Weight          float64 `json:"weight"`
+	MinWeight       float64 `json:"min_weight"`
+	MaxWeight       float64 `json:"max_weight"`
+	Skirmish        bool    `json:"skirmish"`
+	Side            int     `json:"side"`
+	BaseDefense     bool    `json:"base_defense"`
+	Team2           string  `json:"team2"`
+	Easy            bool    `json:"easy"`
+	Normal          bool    `json:"normal"`
+	Hard            bool    `json:"hard"`
+}
+
+// AI 以 aimd.ini 的结构读写 Rules。
+type AI struct {
+	f *ini.File
+}
+
+func NewAI(r *Rules) *AI {
+	return &AI{
+		f: r.f,
+	}
+}
+
+// NewID 返回一个未被使用的 ID，格式与游戏自带的 ID 一致，如 0100003A-G。
+func (ai *AI) NewID() string {
+	maxID := int64(0)
+	for _, list := range []SectionName{SectionNameTaskForces, SectionNameScriptTypes, SectionNameTeamTypes} {
+		for _, id := range ai.registered(list) {
+			maxID = max(maxID, parseAIID(id))
+		}
+	}
+	for _, id := range ai.triggerIDs() {
+		maxID = max(maxID, parseAIID(id))
+	}
+	return fmt.Sprintf("%08X-G", maxID+1)
+}
+
+func (ai *AI) TaskForces() ([]*TaskForce, error) {
+	var taskForces []*TaskForce
+	for _, id := range ai.registered(SectionNameTaskForces) {
+		tf, err := ai.TaskForce(id)
+		if err != nil {
+			return nil, err
+		}
+		taskForces = append(taskForces, tf)
+	}
+	return taskForces, nil
+}
+
+// TaskForce 返回 id 对应的 TaskForce，未注册时返回 nil。
+func (ai *AI) TaskForce(id string) (*TaskForce, error) {
+	if !ai.isRegistered(SectionNameTaskForces, id) {
+		return nil, nil
+	}
+	tf := &TaskForce{ID: id, Group: -1}
+	sec, err := ai.f.GetSection(id)
+	if err != nil {
+		return tf, nil
+	}
+	tf.Name = sec.Key("Name").String()
+	if sec.HasKey("Group") {
+		tf.Group = cast.ToInt(sec.Key("Group").String())
+	}
+	for _, key := range indexedKeys(sec) {
+		count, typ, ok := strings.Cut(key.Value(), ",")
+		if !ok {
+			return nil, errors.Errorf("invalid task force member %s=%s in %s", key.Name(), key.Value(), id)
+		}
+		tf.Members = append(tf.Members, TaskForceMember{
+			Count: cast.ToInt(strings.TrimSpace(count)),
+			Type:  strings.TrimSpace(typ),
+		})
+	}
+	return tf, nil
+}
+
+// SetTaskForce 注册并写入 tf，已有的 TaskForce 在原位置更新。
+func (ai *AI) SetTaskForce(tf *TaskForce) error {
+	sec, err := ai.prepare(SectionNameTaskForces, tf.ID)
+	if err != nil {
+		return err
+	}
+	sec.Key("Name").SetValue(tf.Name)
+	values := make([]string, len(tf.Members))
+	for i, m := range tf.Members {
+		values[i] = fmt.Sprintf("%d,%s", m.Count, m.Type)
+	}
+	setIndexedKeys(sec, values)
+	sec.Key("Group").SetValue(strconv.Itoa(tf.Group))
+	return nil
+}
+
+func (ai *AI) DelTaskForce(id string) error {
+	return ai.unregister(SectionNameTaskForces, id)
+}
+
+func (ai *AI) ScriptTypes() ([]*ScriptType, error) {
+	var scripts []*ScriptType
+	for _, id := range ai.registered(SectionNameScriptTypes) {
+		st, err := ai.ScriptType(id)
+		if err != nil {
+			return nil, err
+		}
+		scripts = append(scripts, st)
+	}
+	return scripts, nil
+}
+
+// ScriptType 返回 id 对应的 ScriptType，未注册时返回 nil。
+func (ai *AI) ScriptType(id string) (*ScriptType, error) {
+	if !ai.isRegistered(SectionNameScriptTypes, id) {
+		return nil, nil
+	}
+	st := &ScriptType{ID: id}
+	sec, err := ai.f.GetSection(id)
+	if err != nil {
+		return st, nil
+	}
+	st.Name = sec.Key("Name").String()
+	for _, key := range indexedKeys(sec) {
+		action, arg, ok := strings.Cut(key.Value(), ",")
+		if !ok {
+			return nil, errors.Errorf("invalid script action %s=%s in %s", key.Name(), key.Value(), id)
+		}
+		st.Actions = append(st.Actions, ScriptAction{
+			Action:   cast.ToInt(strings.TrimSpace(action)),
+			Argument: cast.ToInt(strings.TrimSpace(arg)),
+		})
+	}
+	return st, nil
+}
+
+func (ai *AI) SetScriptType(st *ScriptType) error {
+	sec, err := ai.prepare(SectionNameScriptTypes, st.ID)
+	if err != nil {
+		return err
+	}
+	sec.Key("Name").SetValue(st.Name)
+	values := make([]string, len(st.Actions))
+	for i, a := range st.Actions {
+		values[i] = fmt.Sprintf("%d,%d", a.Action, a.Argument)
+	}
+	setIndexedKeys(sec, values)
+	return nil
+}
+
+func (ai *AI) DelScriptType(id string) error {
+	return ai.unregister(SectionNameScriptTypes, id)
+}
+
+func (ai *AI) TeamTypes() ([]*TeamType, error) {
+	var teams []*TeamType
+	for _, id := range ai.registered(SectionNameTeamTypes) {
+		tt, err := ai.TeamType(id)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, tt)
+	}
+	return teams, nil
+}
+
+// TeamType 返回 id 对应的 TeamType，未注册时返回 nil。
+func (ai *AI) TeamType(id string) (*TeamType, error) {
+	if !ai.isRegistered(SectionNameTeamTypes, id) {
+		return nil, nil
+	}
+	tt := &TeamType{ID: id}
+	sec, err := ai.f.GetSection(id)
+	if err != nil {
+		return tt, nil
+	}
+	for _, prop := range parseProperties(sec) {
+		switch prop.Key {
+		case "Name":
+			tt.Name = prop.Value
+		case "TaskForce":
+			tt.TaskForce = prop.Value
+		case "Script":
+			tt.Script = prop.Value
+		default:
+			tt.Properties = append(tt.Properties, prop)
+		}
+	}
+	return tt, nil
+}
+
+// SetTeamType 写入 tt，不在 Properties 中的其他 key 会被删除。
+func (ai *AI) SetTeamType(tt *TeamType) error {
+	sec, err := ai.prepare(SectionNameTeamTypes, tt.ID)
+	if err != nil {
+		return err
+	}
+	sec.Key("Name").SetValue(tt.Name)
+	sec.Key("TaskForce").SetValue(tt.TaskForce)
+	sec.Key("Script").SetValue(tt.Script)
+	keep := map[string]bool{"Name": true, "TaskForce": true, "Script": true}
+	for _, prop := range tt.Properties {
+		keep[prop.Key] = true
+	}
+	for _, key := range sec.KeyStrings() {
+		if !keep[key] {
+			sec.DeleteKey(key)
+		}
+	}
+	for _, prop := range tt.Properties {
+		k, err := sec.NewKey(prop.Key, prop.Value)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		k.Comment = prop.Comment
+	}
+	return nil
+}
+
+func (ai *AI) DelTeamType(id string) error {
+	return ai.unregister(SectionNameTeamTypes, id)
+}
+
+func (ai *AI) AITriggerTypes() ([]*AITriggerType, error) {
+	var triggers []*AITriggerType
+	for _, id := range ai.triggerIDs() {
+		trigger, err := ai.AITriggerType(id)
+		if err != nil {
+			return nil, err
+		}
+		triggers = append(triggers, trigger)
+	}
+	return triggers, nil
+}
+
+// AITriggerType 返回 id 对应的 AITriggerType，不存在时返回 nil。
+func (ai *AI) AITriggerType(id string) (*AITriggerType, error) {
+	sec, err := ai.f.GetSection(string(SectionNameAITriggerTypes))
+	if err != nil || !sec.HasKey(id) {
+		return nil, nil
+	}
+	fields := strings.Split(sec.Key(id).Value(), ",")
+	if len(fields) != aiTriggerFields {
+		return nil, errors.Errorf("invalid ai trigger %s: want %d fields, got %d", id, aiTriggerFields, len(fields))
+	}
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	return &AITriggerType{
+		ID:              id,
+		Name:            fields[0],
+		Team1:           fields[1],
+		House:           fields[2],
+		TechLevel:       cast.ToInt(fields[3]),
+		ConditionType:   cast.ToInt(fields[4]),
+		ConditionObject: fields[5],
+		Comparator:      fields[6],
+		Weight:          cast.ToFloat64(fields[7]),
+		MinWeight:       cast.ToFloat64(fields[8]),
+		MaxWeight:       cast.ToFloat64(fields[9]),
+		Skirmish:        fields[10] == "1",
+		Side:            cast.ToInt(fields[12]),
+		BaseDefense:     fields[13] == "1",
+		Team2:           fields[14],
+		Easy:            fields[15] == "1",
+		Normal:          fields[16] == "1",
+		Hard:            fields[17] == "1",
+	}, nil
+}
+
+func (ai *AI) SetAITriggerType(t *AITriggerType) error {
+	if t.ID == "" {
+		return errors.New("ai trigger id is empty")
+	}
+	team2 := t.Team2
+	if team2 == "" {
+		team2 = NoneValue
+	}
+	value := strings.Join([]string{
+		t.Name,
+		t.Team1,
+		t.House,
+		strconv.Itoa(t.TechLevel),
+		strconv.Itoa(t.ConditionType),
+		t.ConditionObject,
+		t.Comparator,
+		fmt.Sprintf("%f", t.Weight),
+		fmt.Sprintf("%f", t.MinWeight),
+		fmt.Sprintf("%f", t.MaxWeight),
+		formatBool(t.Skirmish),
+		"0", // 未使用
+		strconv.Itoa(t.Side),
+		formatBool(t.BaseDefense),
+		team2,
+		formatBool(t.Easy),
+		formatBool(t.Normal),
+		formatBool(t.Hard),
+	}, ",")
+	ai.f.Section(string(SectionNameAITriggerTypes)).Key(t.ID).SetValue(value)
+	return nil
+}
+
+func (ai *AI) DelAITriggerType(id string) error {
+	sec, err := ai.f.GetSection(string(SectionNameAITriggerTypes))
+	if err != nil || !sec.HasKey(id) {
+		return errors.New("ai trigger not found")
+	}
+	sec.DeleteKey(id)
+	return nil
+}
+
+// References 返回引用了 id 的 TeamType 与 AITriggerType。
+func (ai *AI) References(id string) ([]string, error) {
+	var refs []string
+	teams, err := ai.TeamTypes()
+	if err != nil {
+		return nil, err
+	}
+	for _, tt := range teams {
+		if tt.TaskForce == id || tt.Script == id {
+			refs = append(refs, tt.ID)
+		}
+	}
+	triggers, err := ai.AITriggerTypes()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range triggers {
+		if t.Team1 == id || t.Team2 == id {
+			refs = append(refs, t.ID)
+		}
+	}
+	return refs, nil
+}
+
+// Validate 检查 TaskForce 的成员是否为 rules 中注册的单位，以及引用的脚本、兵力和小队是否存在。
+func (ai *AI) Validate(rules *Rules) []Problem {
+	var problems []Problem
+	report := func(section, format string, args ...any) {
+		problems = append(problems, newProblem(section, "", format, args...))
+	}
+
+	units := make(map[string]bool)
+	for _, unit := range rules.Units() {
+		units[unit.Name] = true
+	}
+	for _, list := range []SectionName{SectionNameTaskForces, SectionNameScriptTypes, SectionNameTeamTypes} {
+		for _, id := range ai.registered(list) {
+			if !ai.f.HasSection(id) {
+				report(id, "%s entry has no section", list)
+			}
+		}
+	}
+
+	taskForces, err := ai.TaskForces()
+	if err != nil {
+		report(string(SectionNameTaskForces), "%v", err)
+	}
+	for _, tf := range taskForces {
+		for _, m := range tf.Members {
+			if !units[m.Type] {
+				report(tf.ID, "member %s is not a registered unit type", m.Type)
+			}
+			if m.Count <= 0 {
+				report(tf.ID, "member %s has invalid count %d", m.Type, m.Count)
+			}
+		}
+	}
+	if _, err := ai.ScriptTypes(); err != nil {
+		report(string(SectionNameScriptTypes), "%v", err)
+	}
+
+	teams, err := ai.TeamTypes()
+	if err != nil {
+		report(string(SectionNameTeamTypes), "%v", err)
+	}
+	for _, tt := range teams {
+		if !ai.isRegistered(SectionNameTaskForces, tt.TaskForce) {
+			report(tt.ID, "task force %s not found", tt.TaskForce)
+		}
+		if !ai.isRegistered(SectionNameScriptTypes, tt.Script) {
+			report(tt.ID, "script %s not found", tt.Script)
+		}
+	}
+
+	triggers, err := ai.AITriggerTypes()
+	if err != nil {
+		report(string(SectionNameAITriggerTypes), "%v", err)
+	}
+	for _, t := range triggers {
+		if !ai.isRegistered(SectionNameTeamTypes, t.Team1) {
+			report(t.ID, "team %s not found", t.Team1)
+		}
+		if t.Team2 != NoneValue && !ai.isRegistered(SectionNameTeamTypes, t.Team2) {
+			report(t.ID, "team %s not found", t.Team2)
+		}
+	}
+	return problems
+}
+
+// registered 按注册表中的顺序返回 list 中的 ID。
+func (ai *AI) registered(list SectionName) []string {
+	return registered(ai.f, list)
+}
+
+func (ai *AI) isRegistered(list SectionName, id string) bool {
+	return slices.Contains(ai.registered(list), id)
+}
+
+func (ai *AI) triggerIDs() []string {
+	sec, err := ai.f.GetSection(string(SectionNameAITriggerTypes))
+	if err != nil {
+		return nil
+	}
+	return sec.KeyStrings()
+}
+
+// prepare 将 id 注册到 list 并返回其 section，section 不存在时创建。
+func (ai *AI) prepare(list SectionName, id string) (*ini.Section, error) {
+	if id == "" {
+		return nil, errors.New("id is empty")
+	}
+	if !ai.isRegistered(list, id) {
+		if err := register(ai.f, list, id); err != nil {
+			return nil, err
+		}
+	}
+	return ai.f.Section(id), nil
+}
+
+func (ai *AI) unregister(list SectionName, id string) error {
+	return unregister(ai.f, list, id)
+}
+
+// indexedKeys 按序号返回 section 中以数字命名的 key，如 TaskForce 的成员。
+func indexedKeys(sec *ini.Section) []*ini.Key {
+	var keys []*ini.Key
+	for _, key := range sec.Keys() {
+		if _, err := strconv.Atoi(key.Name()); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortStableFunc(keys, func(a, b *ini.Key) int {
+		return cast.ToInt(a.Name()) - cast.ToInt(b.Name())
+	})
+	return keys
+}
+
+// setIndexedKeys 将 values 依次写入 0、1、2…，并删除多余的数字 key。
+func setIndexedKeys(sec *ini.Section, values []string) {
+	for _, key := range indexedKeys(sec) {
+		if cast.ToInt(key.Name()) >= len(values) {
+			sec.DeleteKey(key.Name())
+		}
+	}
+	for i, value := range values {
+		sec.Key(strconv.Itoa(i)).SetValue(value)
+	}
+}
+
+// parseAIID 解析 ID 中 - 之前的十六进制序号，无法解析时返回 0。
+func parseAIID(id string) int64 {
+	prefix, _, _ := strings.Cut(id, "-")
+	n, err := strconv.ParseInt(prefix, 16, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func formatBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,185 @@
+package ra2
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+const testAI = `[TaskForces]
+0=0100003A-G
+
+[0100003A-G]
+Name=Soviet Rhinos
+0=4,HTNK
+1=2,E2
+Group=-1
+
+[ScriptTypes]
+0=0100003B-G
+
+[0100003B-G]
+Name=Attack Base
+0=0,2
+1=49,0
+
+[TeamTypes]
+0=0100003C-G
+
+[0100003C-G]
+Max=5
+Name=Soviet Rhino Team
+Script=0100003B-G
+TaskForce=0100003A-G
+Priority=5
+
+[AITriggerTypes]
+0100003D-G=Rhino Attack,0100003C-G,<all>,1,-1,<none>,0000000000000000000000000000000000000000000000000000000000000000,50.000000,30.000000,50.000000,1,0,2,0,<none>,1,1,1
+`
+
+func newTestAI(content string) *AI {
+	return NewAI(newTestRules(content))
+}
+
+func TestAI_Parse(t *testing.T) {
+	ai := newTestAI(testAI)
+
+	tf, err := ai.TaskForce("0100003A-G")
+	require.NoError(t, err)
+	assert.Equal(t, &TaskForce{
+		ID:    "0100003A-G",
+		Name:  "Soviet Rhinos",
+		Group: -1,
+		Members: []TaskForceMember{
+			{Count: 4, Type: "HTNK"},
+			{Count: 2, Type: "E2"},
+		},
+	}, tf)
+
+	st, err := ai.ScriptType("0100003B-G")
+	require.NoError(t, err)
+	assert.Equal(t, []ScriptAction{{Action: 0, Argument: 2}, {Action: 49, Argument: 0}}, st.Actions)
+
+	tt, err := ai.TeamType("0100003C-G")
+	require.NoError(t, err)
+	assert.Equal(t, "0100003A-G", tt.TaskForce)
+	assert.Equal(t, "0100003B-G", tt.Script)
+	assert.Equal(t, []string{"Max", "Priority"}, lo.Map(tt.Properties, func(p Property, _ int) string { return p.Key }))
+
+	trigger, err := ai.AITriggerType("0100003D-G")
+	require.NoError(t, err)
+	assert.Equal(t, "0100003C-G", trigger.Team1)
+	assert.Equal(t, NoneValue, trigger.Team2)
+	assert.Equal(t, 50.0, trigger.Weight)
+	assert.True(t, trigger.Skirmish)
+	assert.Equal(t, 2, trigger.Side)
+
+	assert.Equal(t, "0100003E-G", ai.NewID())
+}
+
+func TestAI_RoundTrip(t *testing.T) {
+	ai := newTestAI(testAI)
+	want := lo.Must((&Rules{f: ai.f}).Content())
+
+	tf := lo.Must(ai.TaskForce("0100003A-G"))
+	require.NoError(t, ai.SetTaskForce(tf))
+	st := lo.Must(ai.ScriptType("0100003B-G"))
+	require.NoError(t, ai.SetScriptType(st))
+	tt := lo.Must(ai.TeamType("0100003C-G"))
+	require.NoError(t, ai.SetTeamType(tt))
+	trigger := lo.Must(ai.AITriggerType("0100003D-G"))
+	require.NoError(t, ai.SetAITriggerType(trigger))
+
+	assert.Equal(t, string(want), string(lo.Must((&Rules{f: ai.f}).Content())))
+}
+
+func TestAI_SetTaskForce(t *testing.T) {
+	ai := newTestAI(testAI)
+
+	require.NoError(t, ai.SetTaskForce(&TaskForce{
+		ID:      "0100003A-G",
+		Name:    "Soviet Rhinos",
+		Group:   -1,
+		Members: []TaskForceMember{{Count: 6, Type: "HTNK"}},
+	}))
+	sec := ai.f.Section("0100003A-G")
+	assert.Equal(t, []string{"Name", "0", "Group"}, sec.KeyStrings())
+	assert.Equal(t, "6,HTNK", sec.Key("0").String())
+
+	id := ai.NewID()
+	require.NoError(t, ai.SetTaskForce(&TaskForce{ID: id, Name: "new", Group: -1}))
+	assert.Equal(t, id, ai.f.Section("TaskForces").Key("1").String())
+
+	require.NoError(t, ai.DelTaskForce(id))
+	assert.False(t, ai.f.HasSection(id))
+	assert.Equal(t, []string{"0"}, ai.f.Section("TaskForces").KeyStrings())
+	assert.Error(t, ai.DelTaskForce(id))
+}
+
+func TestAI_References(t *testing.T) {
+	ai := newTestAI(testAI)
+	assert.Equal(t, []string{"0100003C-G"}, lo.Must(ai.References("0100003A-G")))
+	assert.Equal(t, []string{"0100003D-G"}, lo.Must(ai.References("0100003C-G")))
+	assert.Empty(t, lo.Must(ai.References("0100003D-G")))
+}
+
+func TestAI_Validate(t *testing.T) {
+	rules := &Rules{f: lo.Must(ini.Load([]byte("[InfantryTypes]\n0=E2\n[VehicleTypes]\n0=HTNK")))}
+
+	tests := []struct {
+		name    string
+		prepare func(ai *AI)
+		want    []Problem
+	}{
+		{
+			name:    "valid",
+			prepare: func(ai *AI) {},
+		},
+		{
+			name: "unknown member",
+			prepare: func(ai *AI) {
+				ai.f.Section("0100003A-G").Key("1").SetValue("2,E9")
+			},
+			want: []Problem{{Section: "0100003A-G", Message: "member E9 is not a registered unit type"}},
+		},
+		{
+			name: "missing references",
+			prepare: func(ai *AI) {
+				require.NoError(t, ai.DelScriptType("0100003B-G"))
+				require.NoError(t, ai.DelTeamType("0100003C-G"))
+			},
+			want: []Problem{{Section: "0100003D-G", Message: "team 0100003C-G not found"}},
+		},
+		{
+			name: "missing script",
+			prepare: func(ai *AI) {
+				require.NoError(t, ai.DelScriptType("0100003B-G"))
+			},
+			want: []Problem{{Section: "0100003C-G", Message: "script 0100003B-G not found"}},
+		},
+		{
+			name: "unregistered section",
+			prepare: func(ai *AI) {
+				ai.f.Section("ScriptTypes").Key("1").SetValue("01000099-G")
+			},
+			want: []Problem{{Section: "01000099-G", Message: "ScriptTypes entry has no section"}},
+		},
+		{
+			name: "malformed trigger",
+			prepare: func(ai *AI) {
+				ai.f.Section("AITriggerTypes").Key("0100003D-G").SetValue("Rhino Attack,0100003C-G")
+			},
+			want: []Problem{{Section: "AITriggerTypes", Message: "invalid ai trigger 0100003D-G: want 18 fields, got 2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ai := newTestAI(testAI)
+			tt.prepare(ai)
+			assert.Equal(t, tt.want, ai.Validate(rules))
+		})
+	}
+}
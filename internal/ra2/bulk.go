@@ -0,0 +1,172 @@
+package ra2
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// BulkOp 是批量编辑对每个匹配 section 的修改方式。
+type BulkOp string
+
+const (
+	BulkOpSet    BulkOp = "set"    // 设置为 Value
+	BulkOpScale  BulkOp = "scale"  // 数值乘以 Value
+	BulkOpAdd    BulkOp = "add"    // 数值加上 Value
+	BulkOpAppend BulkOp = "append" // 在列表末尾追加 Value，已包含时不变
+	BulkOpRemove BulkOp = "remove" // 按 Action 沿用下层的值或清空
+)
+
+// BulkEdit 对 Query 匹配的所有 section 的 Key 执行同一个修改，Query 的语法见 Query。
+type BulkEdit struct {
+	Query  string    `json:"query"`
+	Op     BulkOp    `json:"op"`
+	Key    string    `json:"key"`
+	Value  string    `json:"value"`
+	Action KeyAction `json:"action,omitempty"` // BulkOpRemove 的处理方式，KeyActionInherit 或 KeyActionClear
+}
+
+// BulkChange 是批量编辑对一个 section 的修改，Old 与 New 为修改前后的生效值。
+// Action 为 KeyActionInherit 时从覆盖层移除 key，New 为空。
+type BulkChange struct {
+	Section string    `json:"section"`
+	Type    UnitType  `json:"type"`
+	Key     string    `json:"key"`
+	Old     string    `json:"old"`
+	New     string    `json:"new"`
+	Action  KeyAction `json:"action,omitempty"`
+}
+
+// Plan 计算 e 在合并后的 rules 上产生的修改，值不变的 section 不在结果中。
+func (e *BulkEdit) Plan(rules *Rules, schema *Schema) ([]BulkChange, error) {
+	if e.Key == "" {
+		return nil, errors.New("key is empty")
+	}
+	var delta float64
+	switch e.Op {
+	case BulkOpSet:
+	case BulkOpRemove:
+		if e.Action != KeyActionInherit && e.Action != KeyActionClear {
+			return nil, errors.Errorf("remove action must be %s or %s", KeyActionInherit, KeyActionClear)
+		}
+	case BulkOpAppend:
+		if strings.TrimSpace(e.Value) == "" {
+			return nil, errors.New("value to append is empty")
+		}
+	case BulkOpScale, BulkOpAdd:
+		n, ok := parseNumber(e.Value)
+		if !ok {
+			return nil, errors.Errorf("%s value %q is not a number", e.Op, e.Value)
+		}
+		delta = n
+	default:
+		return nil, errors.Errorf("unknown bulk op %q", e.Op)
+	}
+	q, err := ParseQuery(e.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []BulkChange
+	for _, res := range rules.Query(q, schema) {
+		t := &queryTarget{sec: rules.f.Section(res.Section), unitType: res.Type, schema: schema}
+		old, _ := t.value(e.Key)
+		change := BulkChange{
+			Section: res.Section,
+			Type:    res.Type,
+			Key:     e.Key,
+			Old:     old,
+		}
+		switch e.Op {
+		case BulkOpSet:
+			change.New = e.Value
+		case BulkOpRemove:
+			if !t.sec.HasKey(e.Key) || e.Action == KeyActionClear && old == "" {
+				continue
+			}
+			change.Action = e.Action
+		case BulkOpAppend:
+			change.New = appendListItem(old, strings.TrimSpace(e.Value))
+		case BulkOpScale, BulkOpAdd:
+			n, ok := parseNumber(old)
+			if !ok {
+				return nil, errors.Errorf("%s: %s=%s is not a number", res.Section, e.Key, old)
+			}
+			if e.Op == BulkOpScale {
+				n *= delta
+			} else {
+				n += delta
+			}
+			// 百分数保留 % 后缀，否则游戏会按比例读取，50% 放大后写成 55 会变为 5500%
+			number, percent := strings.CutSuffix(strings.TrimSpace(old), "%")
+			change.New = formatNumber(n, t.integer(e.Key, number))
+			if percent {
+				change.New += "%"
+			}
+		}
+		if change.Action == KeyActionSet && change.New == old {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// ApplyBulk 将 changes 写入覆盖层 r，below 为 r 之下合并后的规则。
+// 与下层相同的值不再覆盖；KeyActionInherit 从 r 中移除 key，KeyActionClear 写入空值。
+func (r *Rules) ApplyBulk(below *Rules, changes []BulkChange) {
+	// 修改的可能是注册表
+	defer r.invalidate()
+	for _, change := range changes {
+		switch change.Action {
+		case KeyActionInherit:
+			if sec, err := r.f.GetSection(change.Section); err == nil {
+				sec.DeleteKey(change.Key)
+			}
+		case KeyActionClear:
+			r.f.Section(change.Section).Key(change.Key).SetValue("")
+		default:
+			origin, hasOrigin := below.Value(change.Section, change.Key)
+			sec := r.f.Section(change.Section)
+			if hasOrigin && origin == change.New {
+				sec.DeleteKey(change.Key)
+			} else {
+				sec.Key(change.Key).SetValue(change.New)
+			}
+		}
+	}
+}
+
+// integer 返回 key 是否为整数，schema 中没有记录时看原值是否为整数。
+func (t *queryTarget) integer(key, value string) bool {
+	if t.schema != nil {
+		if flag, ok := t.schema.Flag(t.unitType, t.sec.Name(), key); ok {
+			return flag.ValueType == "int"
+		}
+	}
+	_, err := strconv.Atoi(strings.TrimSpace(value))
+	return err == nil
+}
+
+func formatNumber(n float64, integer bool) string {
+	if integer {
+		return strconv.Itoa(int(math.Round(n)))
+	}
+	// 去掉浮点运算的误差，如 50*1.1 得到的 55.00000000000001
+	return strconv.FormatFloat(math.Round(n*1e6)/1e6, 'f', -1, 64)
+}
+
+// appendListItem 在逗号分隔的列表 list 末尾追加 item，已包含时原样返回。
+func appendListItem(list, item string) string {
+	if strings.TrimSpace(list) == "" {
+		return item
+	}
+	for _, v := range strings.Split(list, ",") {
+		if strings.EqualFold(strings.TrimSpace(v), item) {
+			return list
+		}
+	}
+	return list + "," + item
+}
@@ -0,0 +1,120 @@
+package ra2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkEdit_Plan(t *testing.T) {
+	rules := newTestRules(testQueryRules + "[Veteran]\nSpeed=1.2\n[General]\nCrewEscape=50%\n")
+	schema := newTestQuerySchema()
+
+	tests := []struct {
+		name    string
+		edit    BulkEdit
+		want    []BulkChange
+		wantErr string
+	}{
+		{
+			name: "scale",
+			edit: BulkEdit{Query: "type:vehicle and Owner=Russians", Op: BulkOpScale, Key: "Speed", Value: "1.1"},
+			// APOC 的 4*1.1 取整后不变
+			want: []BulkChange{
+				{Section: "HTNK", Type: UnitTypeVehicle, Key: "Speed", Old: "5", New: "6"},
+			},
+		},
+		{
+			name: "add float",
+			edit: BulkEdit{Query: "Speed<2", Op: BulkOpAdd, Key: "Speed", Value: "0.25"},
+			want: []BulkChange{
+				{Section: "Veteran", Key: "Speed", Old: "1.2", New: "1.45"},
+			},
+		},
+		{
+			name: "scale percent",
+			edit: BulkEdit{Query: "CrewEscape", Op: BulkOpScale, Key: "CrewEscape", Value: "1.1"},
+			want: []BulkChange{
+				{Section: "General", Key: "CrewEscape", Old: "50%", New: "55%"},
+			},
+		},
+		{
+			name: "set skips unchanged",
+			edit: BulkEdit{Query: "type:vehicle", Op: BulkOpSet, Key: "Crusher", Value: "no"},
+			want: []BulkChange{
+				{Section: "HTNK", Type: UnitTypeVehicle, Key: "Crusher", Old: "yes", New: "no"},
+			},
+		},
+		{
+			name: "append",
+			edit: BulkEdit{Query: "Owner", Op: BulkOpAppend, Key: "Owner", Value: "British"},
+			want: []BulkChange{
+				{Section: "E1", Type: UnitTypeInfantry, Key: "Owner", Old: "Americans,Russians", New: "Americans,Russians,British"},
+				{Section: "HTNK", Type: UnitTypeVehicle, Key: "Owner", Old: "Russians,Confederation", New: "Russians,Confederation,British"},
+				{Section: "APOC", Type: UnitTypeVehicle, Key: "Owner", Old: "Russians", New: "Russians,British"},
+			},
+		},
+		{
+			name: "remove inherit",
+			edit: BulkEdit{Query: "type:vehicle", Op: BulkOpRemove, Key: "Crusher", Action: KeyActionInherit},
+			want: []BulkChange{
+				{Section: "HTNK", Type: UnitTypeVehicle, Key: "Crusher", Old: "yes", Action: KeyActionInherit},
+				{Section: "APOC", Type: UnitTypeVehicle, Key: "Crusher", Old: "no", Action: KeyActionInherit},
+			},
+		},
+		{
+			name: "remove clear",
+			edit: BulkEdit{Query: "type:vehicle", Op: BulkOpRemove, Key: "Crusher", Action: KeyActionClear},
+			want: []BulkChange{
+				{Section: "HTNK", Type: UnitTypeVehicle, Key: "Crusher", Old: "yes", Action: KeyActionClear},
+				{Section: "APOC", Type: UnitTypeVehicle, Key: "Crusher", Old: "no", Action: KeyActionClear},
+			},
+		},
+		{
+			name:    "remove without action",
+			edit:    BulkEdit{Query: "type:vehicle", Op: BulkOpRemove, Key: "Crusher"},
+			wantErr: "remove action must be inherit or clear",
+		},
+		{
+			name:    "not a number",
+			edit:    BulkEdit{Query: "type:vehicle", Op: BulkOpScale, Key: "Armor", Value: "2"},
+			wantErr: "HTNK: Armor=heavy is not a number",
+		},
+		{
+			name:    "unknown op",
+			edit:    BulkEdit{Query: "type:vehicle", Op: "rename", Key: "Armor"},
+			wantErr: `unknown bulk op "rename"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.edit.Plan(rules, schema)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRules_ApplyBulk(t *testing.T) {
+	below := newTestRules("[HTNK]\nCost=900\nCrusher=yes\n[MTNK]\nCost=800\n")
+	r := newTestRules("[MTNK]\nCost=700\nCrusher=yes\n")
+
+	r.ApplyBulk(below, []BulkChange{
+		{Section: "HTNK", Key: "Cost", Old: "900", New: "990"},
+		{Section: "MTNK", Key: "Cost", Old: "700", New: "800"},
+		// 清空下层已有的 key 写入空值，沿用下层的值时从覆盖层移除
+		{Section: "HTNK", Key: "Crusher", Old: "yes", Action: KeyActionClear},
+		{Section: "MTNK", Key: "Crusher", Old: "yes", Action: KeyActionInherit},
+		// 覆盖层中没有的 section 不会因沿用而创建
+		{Section: "APOC", Key: "Crusher", Old: "no", Action: KeyActionInherit},
+	})
+
+	content, err := r.Content()
+	require.NoError(t, err)
+	assert.Equal(t, "[MTNK]\n\n[HTNK]\nCost    = 990\nCrusher = \n", string(content))
+}
@@ -0,0 +1,178 @@
+package ra2
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FramesPerSecond 是默认游戏速度下每秒的帧数，ROF 以帧为单位。
+const FramesPerSecond = 15
+
+// Veterancy 是单位的老兵等级。
+type Veterancy string
+
+const (
+	VeterancyRookie  Veterancy = "rookie"
+	VeterancyVeteran Veterancy = "veteran"
+	VeterancyElite   Veterancy = "elite"
+)
+
+func NewVeterancy(name string) (Veterancy, bool) {
+	switch name {
+	case "", "rookie":
+		return VeterancyRookie, true
+	case "veteran":
+		return VeterancyVeteran, true
+	case "elite":
+		return VeterancyElite, true
+	default:
+		return "", false
+	}
+}
+
+// CombatCell 是一个攻击方对一个防守方的计算结果。攻击方没有能造成伤害的武器时 Weapon 为空。
+type CombatCell struct {
+	Slot   string  `json:"slot"`   // 使用的武器槽位，取 DPS 最高的武器
+	Weapon string  `json:"weapon"` // 武器名
+	Range  float64 `json:"range"`
+	Damage float64 `json:"damage"` // 每发的实际伤害
+	DPS    float64 `json:"dps"`
+	TTK    float64 `json:"ttk"`   // 从第一发开始到击杀的秒数，无法击杀时为 -1
+	Shots  int     `json:"shots"` // 击杀所需的发数，无法击杀时为 0
+}
+
+// CombatMatrix 是攻击方与防守方两两之间的 DPS 与击杀时间，Cells[i][j] 为 Attackers[i] 攻击 Defenders[j]。
+type CombatMatrix struct {
+	Attackers []string       `json:"attackers"`
+	Defenders []string       `json:"defenders"`
+	Cells     [][]CombatCell `json:"cells"`
+}
+
+// veteranBonus 是 [General] 中老兵能力的倍率。
+type veteranBonus struct {
+	combat float64 // FIREPOWER，伤害倍率
+	armor  float64 // STRONGER，受到的伤害除以该值
+	rof    float64 // ROF，开火间隔倍率
+}
+
+func (r *Rules) veteranBonus() veteranBonus {
+	return veteranBonus{
+		combat: r.number("General", "VeteranCombat", 1),
+		armor:  r.number("General", "VeteranArmor", 1),
+		rof:    r.number("General", "VeteranROF", 1),
+	}
+}
+
+// Combat 计算 attackers 以 attackerLevel 等级攻击 defenders 时的 DPS 与击杀时间。
+// 攻击方在各武器中选用对防守方 DPS 最高的一把，不考虑武器能否攻击空中或水下目标，连发视为同时命中。
+func (r *Rules) Combat(attackers, defenders []*Unit, attackerLevel, defenderLevel Veterancy) *CombatMatrix {
+	bonus := r.veteranBonus()
+	m := &CombatMatrix{
+		Attackers: make([]string, 0, len(attackers)),
+		Defenders: make([]string, 0, len(defenders)),
+		Cells:     make([][]CombatCell, 0, len(attackers)),
+	}
+	for _, defender := range defenders {
+		m.Defenders = append(m.Defenders, defender.Name)
+	}
+	for _, attacker := range attackers {
+		m.Attackers = append(m.Attackers, attacker.Name)
+		weapons := r.combatWeapons(attacker, attackerLevel)
+		row := make([]CombatCell, 0, len(defenders))
+		for _, defender := range defenders {
+			row = append(row, r.combat(weapons, hasAbility(attacker, attackerLevel, "FIREPOWER"),
+				hasAbility(attacker, attackerLevel, "ROF"), defender,
+				hasAbility(defender, defenderLevel, "STRONGER"), bonus))
+		}
+		m.Cells = append(m.Cells, row)
+	}
+	return m
+}
+
+func (r *Rules) combat(weapons []Weapon, firepower, fastROF bool, defender *Unit, stronger bool, bonus veteranBonus) CombatCell {
+	armor := armorIndex(defender.Get("Armor"))
+	best := CombatCell{TTK: -1}
+	for _, weapon := range weapons {
+		if weapon.Damage <= 0 || weapon.ROF <= 0 {
+			continue
+		}
+		damage := float64(weapon.Damage) * weapon.Verses[armor]
+		if firepower {
+			damage *= bonus.combat
+		}
+		if stronger && bonus.armor > 0 {
+			damage /= bonus.armor
+		}
+		rof := float64(weapon.ROF)
+		if fastROF {
+			rof *= bonus.rof
+		}
+		cycle := rof / FramesPerSecond
+		dps := damage * float64(max(weapon.Burst, 1)) / cycle
+		if dps <= best.DPS {
+			continue
+		}
+		best = CombatCell{Slot: weapon.Slot, Weapon: weapon.Name, Range: weapon.Range, Damage: damage, DPS: dps, TTK: -1}
+		if strength, ok := parseNumber(defender.Get("Strength")); ok && strength > 0 {
+			best.Shots = int(math.Ceil(strength / damage))
+			volleys := (best.Shots + max(weapon.Burst, 1) - 1) / max(weapon.Burst, 1)
+			best.TTK = float64(volleys-1) * cycle
+		}
+	}
+	return best
+}
+
+// combatWeapons 返回 unit 在 level 等级时可用的武器。精英单位使用 Elite 开头的槽位，未设置时沿用普通槽位。
+func (r *Rules) combatWeapons(unit *Unit, level Veterancy) []Weapon {
+	slots := []string{"Primary", "Secondary"}
+	if n, err := strconv.Atoi(strings.TrimSpace(unit.Get("WeaponCount"))); err == nil {
+		slots = append(slots, numberedSlots("Weapon", n)...)
+	}
+	var weapons []Weapon
+	for _, slot := range slots {
+		name := strings.TrimSpace(unit.Get(slot))
+		if level == VeterancyElite {
+			if elite := strings.TrimSpace(unit.Get("Elite" + slot)); elite != "" {
+				slot, name = "Elite"+slot, elite
+			}
+		}
+		if name == "" || strings.EqualFold(name, "none") {
+			continue
+		}
+		weapon := r.Weapon(name)
+		weapon.Slot = slot
+		weapons = append(weapons, weapon)
+	}
+	return weapons
+}
+
+// hasAbility 返回 unit 在 level 等级时是否有 ability。精英同时拥有老兵的能力。
+func hasAbility(unit *Unit, level Veterancy, ability string) bool {
+	var abilities []string
+	switch level {
+	case VeterancyElite:
+		abilities = append(splitList(unit.Get("VeteranAbilities")), splitList(unit.Get("EliteAbilities"))...)
+	case VeterancyVeteran:
+		abilities = splitList(unit.Get("VeteranAbilities"))
+	}
+	return slices.ContainsFunc(abilities, func(a string) bool { return strings.EqualFold(a, ability) })
+}
+
+// armorIndex 返回护甲类型在 ArmorTypes 中的位置，未设置或无法识别时按 none 计。
+func armorIndex(armor string) int {
+	i := slices.IndexFunc(ArmorTypes, func(t string) bool { return strings.EqualFold(t, strings.TrimSpace(armor)) })
+	return max(i, 0)
+}
+
+// splitList 拆分逗号分隔的列表，忽略空项。
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
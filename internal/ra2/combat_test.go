@@ -0,0 +1,106 @@
+package ra2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCombatRules = `[General]
+VeteranCombat=1.1
+VeteranArmor=1.5
+VeteranROF=0.6
+[VehicleTypes]
+0=HTNK
+1=MTNK
+2=TRUK
+[HTNK]
+Strength=400
+Armor=heavy
+Primary=120mm
+ElitePrimary=120mmE
+VeteranAbilities=FIREPOWER
+EliteAbilities=ROF
+[MTNK]
+Strength=300
+Armor=heavy
+VeteranAbilities=STRONGER
+[TRUK]
+Strength=200
+[120mm]
+Damage=90
+ROF=60
+Warhead=AP
+[120mmE]
+Damage=90
+ROF=60
+Burst=2
+Warhead=AP
+[AP]
+Verses=100%,100%,100%,100%,100%,50%
+`
+
+func TestRules_Combat(t *testing.T) {
+	rules := newTestRules(testCombatRules)
+	units := func(names ...string) []*Unit {
+		var units []*Unit
+		for _, name := range names {
+			units = append(units, rules.UnitByName(name))
+		}
+		return units
+	}
+
+	tests := []struct {
+		name          string
+		attackerLevel Veterancy
+		defenderLevel Veterancy
+		want          CombatCell
+	}{
+		{
+			name:          "rookie",
+			attackerLevel: VeterancyRookie,
+			defenderLevel: VeterancyRookie,
+			want:          CombatCell{Slot: "Primary", Weapon: "120mm", Damage: 45, DPS: 11.25, TTK: 24, Shots: 7},
+		},
+		{
+			name:          "elite attacker",
+			attackerLevel: VeterancyElite,
+			defenderLevel: VeterancyRookie,
+			want:          CombatCell{Slot: "ElitePrimary", Weapon: "120mmE", Damage: 49.5, DPS: 41.25, TTK: 7.2, Shots: 7},
+		},
+		{
+			name:          "veteran defender",
+			attackerLevel: VeterancyRookie,
+			defenderLevel: VeterancyVeteran,
+			want:          CombatCell{Slot: "Primary", Weapon: "120mm", Damage: 30, DPS: 7.5, TTK: 36, Shots: 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := rules.Combat(units("HTNK", "TRUK"), units("MTNK"), tt.attackerLevel, tt.defenderLevel)
+			assert.Equal(t, []string{"HTNK", "TRUK"}, m.Attackers)
+			assert.Equal(t, []string{"MTNK"}, m.Defenders)
+			require.Len(t, m.Cells, 2)
+
+			got := m.Cells[0][0]
+			assert.Equal(t, tt.want.Slot, got.Slot)
+			assert.Equal(t, tt.want.Weapon, got.Weapon)
+			assert.Equal(t, tt.want.Shots, got.Shots)
+			assert.InDelta(t, tt.want.Damage, got.Damage, 1e-9)
+			assert.InDelta(t, tt.want.DPS, got.DPS, 1e-9)
+			assert.InDelta(t, tt.want.TTK, got.TTK, 1e-9)
+
+			// 没有武器的单位无法击杀
+			assert.Equal(t, []CombatCell{{TTK: -1}}, m.Cells[1])
+		})
+	}
+}
+
+func TestNewVeterancy(t *testing.T) {
+	level, ok := NewVeterancy("")
+	assert.True(t, ok)
+	assert.Equal(t, VeterancyRookie, level)
+	_, ok = NewVeterancy("heroic")
+	assert.False(t, ok)
+}
@@ -0,0 +1,153 @@
+package ra2
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+	"gopkg.in/ini.v1"
+)
+
+const (
+	csfMagic        = " FSC"
+	csfLabelMagic   = " LBL"
+	csfStringMagic  = " RTS"
+	csfWStringMagic = "WRTS"
+)
+
+// csfLanguages 是 CSF 文件头中的语言编号
+var csfLanguages = map[uint32]string{
+	0: "en-US",
+	1: "en-GB",
+	2: "de",
+	3: "fr",
+	4: "es",
+	5: "it",
+	6: "ja",
+	8: "ko",
+	9: "zh-TW",
+}
+
+type csfHeader struct {
+	Magic      [4]byte
+	Version    uint32
+	NumLabels  uint32
+	NumStrings uint32
+	Unused     uint32
+	Language   uint32
+}
+
+// LoadCSF 从游戏的 CSF 字符串表（如 ra2md.csf）中加载翻译。
+func LoadCSF(r io.ReadCloser) (*Translation, error) {
+	br := bufio.NewReader(r)
+
+	var header csfHeader
+	if err := binary.Read(br, binary.LittleEndian, &header); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if string(header.Magic[:]) != csfMagic {
+		return nil, errors.New("invalid csf file")
+	}
+	lang, ok := csfLanguages[header.Language]
+	if !ok {
+		lang = "unknown"
+	}
+
+	f, err := ini.LoadSources(ini.LoadOptions{
+		KeyValueDelimiters: "=",
+		InsensitiveKeys:    true,
+	}, []byte{})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	sec, err := f.NewSection(lang)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	for i := uint32(0); i < header.NumLabels; i++ {
+		label, value, err := readCSFLabel(br)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read label %d", i)
+		}
+		if _, err := sec.NewKey(label, value); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return &Translation{
+		lang: lang,
+		f:    f,
+		sec:  sec,
+	}, nil
+}
+
+// readCSFLabel 读取一个标签及其第一个字符串。
+func readCSFLabel(r io.Reader) (string, string, error) {
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	if string(magic[:]) != csfLabelMagic {
+		return "", "", errors.Errorf("invalid label magic %q", magic)
+	}
+	var numStrings, nameLen uint32
+	if err := binary.Read(r, binary.LittleEndian, &numStrings); err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &nameLen); err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	name := make([]byte, nameLen)
+	if _, err := io.ReadFull(r, name); err != nil {
+		return "", "", errors.WithStack(err)
+	}
+
+	var value string
+	for i := uint32(0); i < numStrings; i++ {
+		s, err := readCSFString(r)
+		if err != nil {
+			return "", "", err
+		}
+		if i == 0 {
+			value = s
+		}
+	}
+	return string(name), value, nil
+}
+
+func readCSFString(r io.Reader) (string, error) {
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return "", errors.WithStack(err)
+	}
+	if string(magic[:]) != csfStringMagic && string(magic[:]) != csfWStringMagic {
+		return "", errors.Errorf("invalid string magic %q", magic)
+	}
+	var length uint32
+	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return "", errors.WithStack(err)
+	}
+	buf := make([]byte, length*2)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", errors.WithStack(err)
+	}
+	// 字符串以按位取反的 UTF-16LE 存储
+	chars := make([]uint16, length)
+	for i := range chars {
+		chars[i] = ^binary.LittleEndian.Uint16(buf[i*2:])
+	}
+
+	if string(magic[:]) == csfWStringMagic {
+		// 附加的 ASCII 字符串，通常是音频文件名，这里不需要
+		var extraLen uint32
+		if err := binary.Read(r, binary.LittleEndian, &extraLen); err != nil {
+			return "", errors.WithStack(err)
+		}
+		if _, err := io.CopyN(io.Discard, r, int64(extraLen)); err != nil {
+			return "", errors.WithStack(err)
+		}
+	}
+	return string(utf16.Decode(chars)), nil
+}
@@ -0,0 +1,25 @@
+package ra2
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadCSF(t *testing.T) {
+	csfFile, err := os.Open("../../data/ra2md.csf")
+	if err != nil {
+		t.Fatalf("failed to open csf file: %v", err)
+	}
+	defer csfFile.Close()
+	translation, err := LoadCSF(csfFile)
+	if err != nil {
+		t.Fatalf("failed to load csf: %v", err)
+	}
+
+	assert.Equal(t, "zh-TW", translation.lang)
+	assert.Equal(t, "美國大兵", translation.Get("Name:E1"))
+	assert.Equal(t, "犀牛坦克", translation.Get("name:htnk"))
+	assert.Equal(t, "開場", translation.Get("THEME:Intro"))
+}
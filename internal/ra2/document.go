@@ -0,0 +1,178 @@
+package ra2
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/ini.v1"
+	"gopkg.in/yaml.v3"
+)
+
+// Document 是 Rules 的结构化表示，保留 section 与 key 的顺序及注释，与 INI 可以无损互相转换。
+type Document struct {
+	Sections []DocumentSection `json:"sections" yaml:"sections"`
+}
+
+// DocumentSection 是一个 section。Type 为该 section 注册的单位类型，导入时未注册的单位会追加到
+// 对应的注册表，便于用模板生成新单位。
+type DocumentSection struct {
+	Name    string        `json:"name" yaml:"name"`
+	Type    UnitType      `json:"type,omitempty" yaml:"type,omitempty"`
+	Comment string        `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Keys    []DocumentKey `json:"keys" yaml:"keys"`
+}
+
+type DocumentKey struct {
+	Key     string `json:"key" yaml:"key"`
+	Value   string `json:"value" yaml:"value"`
+	Comment string `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+// DocumentFormat 是 Document 的序列化格式。
+type DocumentFormat string
+
+const (
+	DocumentFormatJSON DocumentFormat = "json"
+	DocumentFormatYAML DocumentFormat = "yaml"
+)
+
+// DocumentFormatOf 按扩展名判断 filename 的格式。
+func DocumentFormatOf(filename string) (DocumentFormat, bool) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return DocumentFormatJSON, true
+	case ".yaml", ".yml":
+		return DocumentFormatYAML, true
+	default:
+		return "", false
+	}
+}
+
+// Document 返回 r 的结构化表示。
+func (r *Rules) Document() *Document {
+	doc := &Document{Sections: []DocumentSection{}}
+	types := registeredTypes(r.f)
+	for _, sec := range r.f.Sections() {
+		if sec.Name() == ini.DefaultSection && len(sec.Keys()) == 0 && sec.Comment == "" {
+			continue
+		}
+		ds := DocumentSection{
+			Name:    sec.Name(),
+			Comment: sec.Comment,
+			Keys:    []DocumentKey{},
+		}
+		ds.Type = types[sec.Name()]
+		for _, key := range sec.Keys() {
+			ds.Keys = append(ds.Keys, DocumentKey{
+				Key:     key.Name(),
+				Value:   key.Value(),
+				Comment: key.Comment,
+			})
+		}
+		doc.Sections = append(doc.Sections, ds)
+	}
+	return doc
+}
+
+// NewRulesFromDocument 由结构化表示创建 Rules。同名的 section 或 key 视为错误。
+func NewRulesFromDocument(doc *Document) (*Rules, error) {
+	r := NewEmptyRules()
+	seen := make(map[string]bool)
+	for _, ds := range doc.Sections {
+		if ds.Name == "" {
+			return nil, errors.New("section name is empty")
+		}
+		if seen[ds.Name] {
+			return nil, errors.Errorf("duplicate section %s", ds.Name)
+		}
+		seen[ds.Name] = true
+		if ds.Type != UnitTypeUnknown && NewUnitType(string(ds.Type)) == UnitTypeUnknown {
+			return nil, errors.Errorf("section %s: unknown unit type %q", ds.Name, ds.Type)
+		}
+
+		sec := r.f.Section(ds.Name)
+		sec.Comment = ds.Comment
+		for _, dk := range ds.Keys {
+			if dk.Key == "" {
+				return nil, errors.Errorf("section %s: key is empty", ds.Name)
+			}
+			if sec.HasKey(dk.Key) {
+				return nil, errors.Errorf("section %s: duplicate key %s", ds.Name, dk.Key)
+			}
+			key, err := sec.NewKey(dk.Key, dk.Value)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			key.Comment = dk.Comment
+		}
+	}
+
+	// 注册表中没有的单位追加注册
+	types := registeredTypes(r.f)
+	for _, ds := range doc.Sections {
+		if ds.Type == UnitTypeUnknown || types[ds.Name] == ds.Type {
+			continue
+		}
+		if err := register(r.f, ds.Type.Section(), ds.Name); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// registeredTypes 返回各单位注册表中的名称及其类型。与 Rules.index 不同，不会为没有 section 的单位创建 section。
+func registeredTypes(f *ini.File) map[string]UnitType {
+	types := make(map[string]UnitType)
+	for _, unitType := range UnitTypes {
+		for _, name := range registered(f, unitType.Section()) {
+			if _, ok := types[name]; !ok {
+				types[name] = unitType
+			}
+		}
+	}
+	return types
+}
+
+// WriteDocument 以 format 格式写出 doc。
+func WriteDocument(w io.Writer, format DocumentFormat, doc *Document) error {
+	switch format {
+	case DocumentFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return errors.WithStack(enc.Encode(doc))
+	case DocumentFormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return errors.WithStack(err)
+		}
+		return errors.WithStack(enc.Close())
+	default:
+		return errors.Errorf("unknown document format %q", format)
+	}
+}
+
+// ReadDocument 读取 format 格式的 Document。
+func ReadDocument(r io.Reader, format DocumentFormat) (*Document, error) {
+	var doc Document
+	switch format {
+	case DocumentFormatJSON:
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&doc); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	case DocumentFormatYAML:
+		dec := yaml.NewDecoder(r)
+		dec.KnownFields(true)
+		if err := dec.Decode(&doc); err != nil && err != io.EOF {
+			return nil, errors.WithStack(err)
+		}
+	default:
+		return nil, errors.Errorf("unknown document format %q", format)
+	}
+	return &doc, nil
+}
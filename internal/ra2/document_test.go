@@ -0,0 +1,92 @@
+package ra2
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument_RoundTrip(t *testing.T) {
+	f, err := os.Open("../../data/rulesmd.ini")
+	require.NoError(t, err)
+	rules, err := NewRules(f)
+	require.NoError(t, err)
+	want, err := rules.Content()
+	require.NoError(t, err)
+
+	for _, format := range []DocumentFormat{DocumentFormatJSON, DocumentFormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteDocument(&buf, format, rules.Document()))
+			doc, err := ReadDocument(&buf, format)
+			require.NoError(t, err)
+			got, err := NewRulesFromDocument(doc)
+			require.NoError(t, err)
+
+			assert.True(t, compareIni(rules.f, got.f))
+			content, err := got.Content()
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(content))
+		})
+	}
+}
+
+func TestDocument_Comments(t *testing.T) {
+	rules := newTestRules("; vehicles\n[VehicleTypes]\n0=HTNK\n[HTNK]\n; price\nCost=900\nArmor=heavy\n")
+	doc := rules.Document()
+	assert.Equal(t, []DocumentSection{
+		{Name: "VehicleTypes", Comment: "; vehicles", Keys: []DocumentKey{{Key: "0", Value: "HTNK"}}},
+		{Name: "HTNK", Type: UnitTypeVehicle, Keys: []DocumentKey{
+			{Key: "Cost", Value: "900", Comment: "; price"},
+			{Key: "Armor", Value: "heavy"},
+		}},
+	}, doc.Sections)
+}
+
+func TestNewRulesFromDocument(t *testing.T) {
+	// 模板中的新单位只需写出类型，导入时追加注册
+	doc, err := ReadDocument(strings.NewReader(`
+sections:
+  - name: VehicleTypes
+    keys:
+      - {key: "0", value: HTNK}
+  - name: HTNK
+    type: vehicle
+    keys:
+      - {key: Cost, value: 900}
+  - name: TNK2
+    type: vehicle
+    keys:
+      - {key: Cost, value: 1.10}
+      - {key: Crusher, value: yes}
+`), DocumentFormatYAML)
+	require.NoError(t, err)
+	rules, err := NewRulesFromDocument(doc)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"HTNK", "TNK2"}, registered(rules.f, SectionNameVehicle))
+	assert.Equal(t, "1.10", rules.UnitByName("TNK2").Get("Cost"))
+	assert.Equal(t, "yes", rules.UnitByName("TNK2").Get("Crusher"))
+
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{name: "duplicate section", doc: `{"sections":[{"name":"A","keys":[]},{"name":"A","keys":[]}]}`, wantErr: "duplicate section A"},
+		{name: "duplicate key", doc: `{"sections":[{"name":"A","keys":[{"key":"K","value":"1"},{"key":"K","value":"2"}]}]}`, wantErr: "section A: duplicate key K"},
+		{name: "unknown type", doc: `{"sections":[{"name":"A","type":"tank","keys":[]}]}`, wantErr: `section A: unknown unit type "tank"`},
+		{name: "empty name", doc: `{"sections":[{"name":"","keys":[]}]}`, wantErr: "section name is empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := ReadDocument(strings.NewReader(tt.doc), DocumentFormatJSON)
+			require.NoError(t, err)
+			_, err = NewRulesFromDocument(doc)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
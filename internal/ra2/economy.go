@@ -0,0 +1,102 @@
+package ra2
+
+import (
+	"math"
+)
+
+// EconomyOptions 是经济计算的参数。
+type EconomyOptions struct {
+	Factories int    `json:"factories"` // 同类工厂的数量，小于 1 时按 1 计
+	Armor     string `json:"armor"`     // 计算 DPS 时目标的护甲类型，为空时按 none 计
+}
+
+// EconomyRow 是一个单位的建造时间与性价比。无法计算的比值为 0。
+type EconomyRow struct {
+	Name       string   `json:"name"`
+	Type       UnitType `json:"type"`
+	Cost       float64  `json:"cost"`
+	Strength   float64  `json:"strength"`
+	BuildTime  float64  `json:"build_time"` // 秒
+	CostPerHP  float64  `json:"cost_per_hp"`
+	DPS        float64  `json:"dps"`
+	CostPerDPS float64  `json:"cost_per_dps"`
+	Power      float64  `json:"power"` // 正数为发电量，负数为耗电量
+}
+
+// PowerBalance 是一组建筑的电力收支。
+type PowerBalance struct {
+	Output  float64 `json:"output"`
+	Drain   float64 `json:"drain"`
+	Balance float64 `json:"balance"`
+}
+
+// Economy 计算 units 的建造时间、每点生命值的价格、每点 DPS 的价格与电力。
+// 建造时间为 Cost × BuildTimeMultiplier × [General] BuildSpeed 分钟每千元，每多一座工厂乘以一次
+// MultipleFactory，围墙再乘以 WallBuildSpeedCoefficient，不计低电力的减速。
+func (r *Rules) Economy(units []*Unit, opts EconomyOptions) []EconomyRow {
+	buildSpeed := r.number("General", "BuildSpeed", 1)
+	factory := math.Pow(r.number("General", "MultipleFactory", 1), float64(max(opts.Factories, 1)-1))
+	wallSpeed := r.number("General", "WallBuildSpeedCoefficient", 1)
+
+	rows := make([]EconomyRow, 0, len(units))
+	for _, unit := range units {
+		row := EconomyRow{
+			Name:     unit.Name,
+			Type:     unit.Type,
+			Cost:     r.number(unit.Name, "Cost", 0),
+			Strength: r.number(unit.Name, "Strength", 0),
+			Power:    r.number(unit.Name, "Power", 0),
+		}
+		row.BuildTime = row.Cost * r.number(unit.Name, "BuildTimeMultiplier", 1) * buildSpeed * 60 / 1000 * factory
+		if isWall, _ := parseBool(unit.Get("Wall")); isWall {
+			row.BuildTime *= wallSpeed
+		}
+		for _, weapon := range r.combatWeapons(unit, VeterancyRookie) {
+			row.DPS = max(row.DPS, weapon.DPS(opts.Armor))
+		}
+		if row.Strength > 0 {
+			row.CostPerHP = row.Cost / row.Strength
+		}
+		if row.DPS > 0 {
+			row.CostPerDPS = row.Cost / row.DPS
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// PowerBalance 返回 buildings 的电力收支，同一建筑出现多次时按多座计算。
+func (r *Rules) PowerBalance(buildings []*Unit) PowerBalance {
+	var balance PowerBalance
+	for _, building := range buildings {
+		power := r.number(building.Name, "Power", 0)
+		if power > 0 {
+			balance.Output += power
+		} else {
+			balance.Drain -= power
+		}
+	}
+	balance.Balance = balance.Output - balance.Drain
+	return balance
+}
+
+// EconomyColumns 是 EconomyRow 导出为表格时的列名，顺序与 Values 一致。
+var EconomyColumns = []string{"Type", "Cost", "Strength", "BuildTime", "CostPerHP", "DPS", "CostPerDPS", "Power"}
+
+// Values 返回 row 在 EconomyColumns 各列的值，比值保留两位小数。
+func (row EconomyRow) Values() []string {
+	return []string{
+		string(row.Type),
+		formatNumber(row.Cost, false),
+		formatNumber(row.Strength, false),
+		formatRatio(row.BuildTime),
+		formatRatio(row.CostPerHP),
+		formatRatio(row.DPS),
+		formatRatio(row.CostPerDPS),
+		formatNumber(row.Power, false),
+	}
+}
+
+func formatRatio(n float64) string {
+	return formatNumber(math.Round(n*100)/100, false)
+}
@@ -0,0 +1,94 @@
+package ra2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRules_Economy(t *testing.T) {
+	rules := newTestRules(`[General]
+BuildSpeed=.7
+MultipleFactory=0.8
+WallBuildSpeedCoefficient=3.0
+[VehicleTypes]
+0=HTNK
+[BuildingTypes]
+0=NAPOWR
+1=NAWEAP
+2=NAWALL
+[HTNK]
+Cost=900
+Strength=400
+BuildTimeMultiplier=1.5
+Primary=120mm
+[NAPOWR]
+Cost=600
+Strength=750
+Power=150
+[NAWEAP]
+Cost=2000
+Power=-25
+[NAWALL]
+Cost=100
+Strength=300
+Wall=yes
+[120mm]
+Damage=90
+ROF=60
+Warhead=AP
+[AP]
+Verses=25%,100%,100%,100%,100%,100%
+`)
+	units := []*Unit{rules.UnitByName("HTNK"), rules.UnitByName("NAPOWR"), rules.UnitByName("NAWEAP"), rules.UnitByName("NAWALL")}
+
+	tests := []struct {
+		name string
+		opts EconomyOptions
+		want []EconomyRow
+	}{
+		{
+			name: "one factory",
+			opts: EconomyOptions{},
+			want: []EconomyRow{
+				{Name: "HTNK", Type: UnitTypeVehicle, Cost: 900, Strength: 400, BuildTime: 56.7, CostPerHP: 2.25, DPS: 5.625, CostPerDPS: 160},
+				{Name: "NAPOWR", Type: UnitTypeBuilding, Cost: 600, Strength: 750, BuildTime: 25.2, CostPerHP: 0.8, Power: 150},
+				{Name: "NAWEAP", Type: UnitTypeBuilding, Cost: 2000, BuildTime: 84, Power: -25},
+				{Name: "NAWALL", Type: UnitTypeBuilding, Cost: 100, Strength: 300, BuildTime: 12.6, CostPerHP: 1.0 / 3},
+			},
+		},
+		{
+			name: "two factories against heavy armor",
+			opts: EconomyOptions{Factories: 2, Armor: "heavy"},
+			want: []EconomyRow{
+				{Name: "HTNK", Type: UnitTypeVehicle, Cost: 900, Strength: 400, BuildTime: 45.36, CostPerHP: 2.25, DPS: 22.5, CostPerDPS: 40},
+				{Name: "NAPOWR", Type: UnitTypeBuilding, Cost: 600, Strength: 750, BuildTime: 20.16, CostPerHP: 0.8, Power: 150},
+				{Name: "NAWEAP", Type: UnitTypeBuilding, Cost: 2000, BuildTime: 67.2, Power: -25},
+				{Name: "NAWALL", Type: UnitTypeBuilding, Cost: 100, Strength: 300, BuildTime: 10.08, CostPerHP: 1.0 / 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rules.Economy(units, tt.opts)
+			require.Len(t, got, len(tt.want))
+			for i, want := range tt.want {
+				assert.Equal(t, want.Name, got[i].Name)
+				assert.Equal(t, want.Type, got[i].Type)
+				assert.InDelta(t, want.Cost, got[i].Cost, 1e-9)
+				assert.InDelta(t, want.Strength, got[i].Strength, 1e-9)
+				assert.InDelta(t, want.BuildTime, got[i].BuildTime, 1e-9, want.Name)
+				assert.InDelta(t, want.CostPerHP, got[i].CostPerHP, 1e-9)
+				assert.InDelta(t, want.DPS, got[i].DPS, 1e-9)
+				assert.InDelta(t, want.CostPerDPS, got[i].CostPerDPS, 1e-9)
+				assert.InDelta(t, want.Power, got[i].Power, 1e-9)
+			}
+		})
+	}
+
+	assert.Equal(t, []string{"vehicle", "900", "400", "56.7", "2.25", "5.63", "160", "0"},
+		rules.Economy(units[:1], EconomyOptions{})[0].Values())
+	assert.Equal(t, PowerBalance{Output: 300, Drain: 25, Balance: 275},
+		rules.PowerBalance([]*Unit{units[1], units[1], units[2]}))
+}
@@ -0,0 +1,52 @@
+package ra2
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// 尤里的复仇使用的数据文件名
+const (
+	GameFileRules = "rulesmd.ini"
+	GameFileArt   = "artmd.ini"
+	GameFileAI    = "aimd.ini"
+	GameFileSound = "soundmd.ini"
+	GameFileEVA   = "evamd.ini"
+	GameFileCSF   = "ra2md.csf"
+)
+
+// GameFiles 是游戏数据文件的路径，未找到的文件为空。
+// 路径也可以指向 MIX 文件中的文件，如 ra2md.mix/localmd.mix/rulesmd.ini。
+type GameFiles struct {
+	Rules string
+	Art   string
+	AI    string
+	Sound string
+	EVA   string
+	CSF   string
+}
+
+// FindGameFiles 在游戏安装目录或解包目录 dir 中查找数据文件，文件名不区分大小写。
+func FindGameFiles(dir string) (*GameFiles, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	names := make(map[string]string)
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			names[strings.ToLower(entry.Name())] = filepath.Join(dir, entry.Name())
+		}
+	}
+	return &GameFiles{
+		Rules: names[GameFileRules],
+		Art:   names[GameFileArt],
+		AI:    names[GameFileAI],
+		Sound: names[GameFileSound],
+		EVA:   names[GameFileEVA],
+		CSF:   names[GameFileCSF],
+	}, nil
+}
@@ -0,0 +1,28 @@
+package ra2
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindGameFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"RULESMD.INI", "artmd.ini", "Ra2md.csf", "ra2md.mix"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+	}
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "aimd.ini"), 0o755))
+
+	files, err := FindGameFiles(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, &GameFiles{
+		Rules: filepath.Join(dir, "RULESMD.INI"),
+		Art:   filepath.Join(dir, "artmd.ini"),
+		CSF:   filepath.Join(dir, "Ra2md.csf"),
+	}, files)
+
+	_, err = FindGameFiles(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
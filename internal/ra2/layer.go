@@ -0,0 +1,241 @@
+package ra2
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/pkg/errors"
+)
+
+// Layer 是规则栈中的一层，例如原版 rulesmd.ini、社区补丁或个人草稿。
+type Layer struct {
+	ID       string
+	Name     string
+	Path     string // 关联的文件路径，新建的层为空
+	Enabled  bool
+	ReadOnly bool // 只读层不能作为写入目标，也不能被移除
+	Dirty    bool // 有未保存的修改
+
+	Rules *Rules
+	Map   *MapFile // 从地图文件打开的层，保存时写回地图中的其他内容
+}
+
+func NewLayer(name string, rules *Rules) *Layer {
+	return &Layer{
+		ID:      ulid.Make().String(),
+		Name:    name,
+		Enabled: true,
+		Rules:   rules,
+	}
+}
+
+// Content 返回层保存到文件时的内容。
+func (l *Layer) Content() ([]byte, error) {
+	if l.Map != nil {
+		return l.Map.Content(l.Rules)
+	}
+	return l.Rules.Content()
+}
+
+// LayerStack 是按顺序叠加的规则层，下标越大优先级越高。
+type LayerStack struct {
+	layers []*Layer
+	target string
+
+	mu     sync.Mutex
+	merged map[string]*Rules // 合并结果的缓存，key 为 Below 的层 ID，整个栈为空字符串
+}
+
+func NewLayerStack(layers ...*Layer) *LayerStack {
+	return &LayerStack{
+		layers: layers,
+	}
+}
+
+// Layers 按从底到顶的顺序返回所有层。
+func (s *LayerStack) Layers() []*Layer {
+	return slices.Clone(s.layers)
+}
+
+func (s *LayerStack) Layer(id string) *Layer {
+	idx := s.index(id)
+	if idx < 0 {
+		return nil
+	}
+	return s.layers[idx]
+}
+
+// Add 将 layer 压入栈顶。
+func (s *LayerStack) Add(layer *Layer) error {
+	if s.index(layer.ID) >= 0 {
+		return errors.New("layer already exists")
+	}
+	s.layers = append(s.layers, layer)
+	s.Invalidate()
+	return nil
+}
+
+func (s *LayerStack) Remove(id string) error {
+	idx := s.index(id)
+	if idx < 0 {
+		return errors.New("layer not found")
+	}
+	if s.layers[idx].ReadOnly {
+		return errors.New("cannot remove read-only layer")
+	}
+	s.layers = slices.Delete(s.layers, idx, idx+1)
+	if s.target == id {
+		s.target = ""
+	}
+	s.Invalidate()
+	return nil
+}
+
+// Move 将层移动到 index 位置，index 按从底到顶计数。只读的基础层固定在底部，
+// 不能移动，其他层也不能移到基础层之下。
+func (s *LayerStack) Move(id string, index int) error {
+	idx := s.index(id)
+	if idx < 0 {
+		return errors.New("layer not found")
+	}
+	if index < 0 || index >= len(s.layers) {
+		return errors.New("layer index out of range")
+	}
+	layer := s.layers[idx]
+	if layer.ReadOnly {
+		return errors.New("cannot move read-only layer")
+	}
+	readOnly := 0
+	for _, l := range s.layers {
+		if l.ReadOnly {
+			readOnly++
+		}
+	}
+	if index < readOnly {
+		return errors.New("cannot move layer below read-only layers")
+	}
+	s.layers = slices.Delete(s.layers, idx, idx+1)
+	s.layers = slices.Insert(s.layers, index, layer)
+	s.Invalidate()
+	return nil
+}
+
+func (s *LayerStack) SetEnabled(id string, enabled bool) error {
+	layer := s.Layer(id)
+	if layer == nil {
+		return errors.New("layer not found")
+	}
+	layer.Enabled = enabled
+	s.Invalidate()
+	return nil
+}
+
+// SetTarget 指定写入目标层，SaveUnit 等修改只会写入该层。
+func (s *LayerStack) SetTarget(id string) error {
+	layer := s.Layer(id)
+	if layer == nil {
+		return errors.New("layer not found")
+	}
+	if layer.ReadOnly {
+		return errors.New("cannot write to read-only layer")
+	}
+	s.target = id
+	return nil
+}
+
+// Target 返回写入目标层，未指定时返回 nil。
+func (s *LayerStack) Target() *Layer {
+	if s.target == "" {
+		return nil
+	}
+	return s.Layer(s.target)
+}
+
+// Merged 返回所有启用层按顺序合并后的规则。
+// 结果会被缓存并在调用方之间共享，不能修改。
+func (s *LayerStack) Merged() (*Rules, error) {
+	return s.cached("", s.layers)
+}
+
+// Below 返回 id 之下所有启用层合并后的规则，用于判断某层相对下层改了什么。
+// 结果会被缓存并在调用方之间共享，不能修改。
+func (s *LayerStack) Below(id string) (*Rules, error) {
+	idx := s.index(id)
+	if idx < 0 {
+		return nil, errors.New("layer not found")
+	}
+	return s.cached(id, s.layers[:idx])
+}
+
+// Through 返回 id 及其之下所有启用层合并后的规则，即只写入 id 层的修改能产生的效果。
+// 结果同样会被缓存共享，不能修改。
+func (s *LayerStack) Through(id string) (*Rules, error) {
+	idx := s.index(id)
+	if idx < 0 {
+		return nil, errors.New("layer not found")
+	}
+	return s.cached(id+"+", s.layers[:idx+1])
+}
+
+// Invalidate 清除合并结果的缓存。栈的结构变化时会自动清除，
+// 修改层的内容后需要调用方调用。
+func (s *LayerStack) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.merged = nil
+}
+
+func (s *LayerStack) cached(key string, layers []*Layer) (*Rules, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r, ok := s.merged[key]; ok {
+		return r, nil
+	}
+	r, err := s.merge(layers)
+	if err != nil {
+		return nil, err
+	}
+	if s.merged == nil {
+		s.merged = make(map[string]*Rules)
+	}
+	s.merged[key] = r
+	return r, nil
+}
+
+// Source 返回为 section 中 key 提供生效值的层，即定义了该 key 的最上层启用层。
+func (s *LayerStack) Source(section, key string) *Layer {
+	for i := len(s.layers) - 1; i >= 0; i-- {
+		layer := s.layers[i]
+		if !layer.Enabled {
+			continue
+		}
+		sec, err := layer.Rules.f.GetSection(section)
+		if err != nil {
+			continue
+		}
+		if sec.HasKey(key) {
+			return layer
+		}
+	}
+	return nil
+}
+
+func (s *LayerStack) merge(layers []*Layer) (*Rules, error) {
+	r := NewEmptyRules()
+	for _, layer := range layers {
+		if !layer.Enabled {
+			continue
+		}
+		if err := mergeIni(r.f, layer.Rules.f); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return r, nil
+}
+
+func (s *LayerStack) index(id string) int {
+	return slices.IndexFunc(s.layers, func(l *Layer) bool {
+		return l.ID == id
+	})
+}
@@ -0,0 +1,145 @@
+package ra2
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/ini.v1"
+)
+
+func newTestLayer(name, content string) *Layer {
+	return NewLayer(name, &Rules{f: lo.Must(ini.Load([]byte(content)))})
+}
+
+func TestLayerStack_Merged(t *testing.T) {
+	base := newTestLayer("base", "[E1]\nCost=100\nStrength=125")
+	patch := newTestLayer("patch", "[E1]\nCost=120")
+	mod := newTestLayer("mod", "[E1]\nCost=150\nSpeed=5")
+
+	tests := []struct {
+		name    string
+		prepare func(s *LayerStack)
+		want    string
+	}{
+		{
+			name:    "all enabled",
+			prepare: func(s *LayerStack) {},
+			want:    "[E1]\nCost=150\nStrength=125\nSpeed=5",
+		},
+		{
+			name: "disable top",
+			prepare: func(s *LayerStack) {
+				assert.NoError(t, s.SetEnabled(mod.ID, false))
+			},
+			want: "[E1]\nCost=120\nStrength=125",
+		},
+		{
+			name: "reorder",
+			prepare: func(s *LayerStack) {
+				assert.NoError(t, s.Move(mod.ID, 1))
+			},
+			want: "[E1]\nCost=120\nStrength=125\nSpeed=5",
+		},
+		{
+			name: "remove",
+			prepare: func(s *LayerStack) {
+				assert.NoError(t, s.Remove(patch.ID))
+			},
+			want: "[E1]\nCost=150\nStrength=125\nSpeed=5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, patch, mod := *base, *patch, *mod
+			s := NewLayerStack(&base, &patch, &mod)
+			tt.prepare(s)
+			got, err := s.Merged()
+			assert.NoError(t, err)
+			assert.True(t, compareIni(got.f, lo.Must(ini.Load([]byte(tt.want)))))
+		})
+	}
+}
+
+func TestLayerStack_Source(t *testing.T) {
+	base := newTestLayer("base", "[E1]\nCost=100\nStrength=125")
+	mod := newTestLayer("mod", "[E1]\nCost=150")
+	s := NewLayerStack(base, mod)
+
+	assert.Equal(t, mod, s.Source("E1", "Cost"))
+	assert.Equal(t, base, s.Source("E1", "Strength"))
+	assert.Nil(t, s.Source("E1", "Speed"))
+
+	assert.NoError(t, s.SetEnabled(mod.ID, false))
+	assert.Equal(t, base, s.Source("E1", "Cost"))
+}
+
+func TestLayerStack_Target(t *testing.T) {
+	base := newTestLayer("base", "")
+	base.ReadOnly = true
+	mod := newTestLayer("mod", "")
+	s := NewLayerStack(base, mod)
+
+	assert.Nil(t, s.Target())
+	assert.Error(t, s.SetTarget(base.ID))
+	assert.NoError(t, s.SetTarget(mod.ID))
+	assert.Equal(t, mod, s.Target())
+	assert.Error(t, s.Remove(base.ID))
+	assert.NoError(t, s.Remove(mod.ID))
+	assert.Nil(t, s.Target())
+}
+
+func TestLayerStack_MoveReadOnly(t *testing.T) {
+	base := newTestLayer("base", "")
+	base.ReadOnly = true
+	patch := newTestLayer("patch", "")
+	mod := newTestLayer("mod", "")
+	s := NewLayerStack(base, patch, mod)
+
+	assert.EqualError(t, s.Move(base.ID, 2), "cannot move read-only layer")
+	assert.EqualError(t, s.Move(mod.ID, 0), "cannot move layer below read-only layers")
+	assert.NoError(t, s.Move(mod.ID, 1))
+	assert.Equal(t, []*Layer{base, mod, patch}, s.Layers())
+}
+
+func TestLayerStack_MergedCache(t *testing.T) {
+	base := newTestLayer("base", "[E1]\nCost=100")
+	mod := newTestLayer("mod", "[E1]\nCost=150")
+	s := NewLayerStack(base, mod)
+
+	first := lo.Must(s.Merged())
+	assert.Same(t, first, lo.Must(s.Merged()))
+	assert.Equal(t, "100", lo.Must(s.Below(mod.ID)).f.Section("E1").Key("Cost").String())
+	assert.Equal(t, "150", lo.Must(s.Through(mod.ID)).f.Section("E1").Key("Cost").String())
+	assert.Equal(t, "100", lo.Must(s.Through(base.ID)).f.Section("E1").Key("Cost").String())
+
+	mod.Rules.f.Section("E1").Key("Cost").SetValue("200")
+	assert.Same(t, first, lo.Must(s.Merged()))
+	s.Invalidate()
+	assert.Equal(t, "200", lo.Must(s.Merged()).f.Section("E1").Key("Cost").String())
+
+	assert.NoError(t, s.SetEnabled(mod.ID, false))
+	assert.Equal(t, "100", lo.Must(s.Merged()).f.Section("E1").Key("Cost").String())
+}
+
+func BenchmarkLayerStack_Merged(b *testing.B) {
+	base := NewLayer("base", loadTestRules(b))
+	mod := newTestLayer("mod", "[E1]\nCost=150")
+	s := NewLayerStack(base, mod)
+
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := s.Merged(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("invalidated", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			s.Invalidate()
+			if _, err := s.Merged(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
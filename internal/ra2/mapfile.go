@@ -0,0 +1,242 @@
+package ra2
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/ini.v1"
+)
+
+// MapFileExts 是可以作为覆盖层打开的地图文件扩展名
+var MapFileExts = []string{".map", ".yrm", ".mpr"}
+
+// mapSections 是地图自身的 section，不属于规则覆盖，保存时原样写回
+var mapSections = map[string]bool{
+	"Basic":                true,
+	"Map":                  true,
+	"Header":               true,
+	"Preview":              true,
+	"PreviewPack":          true,
+	"IsoMapPack5":          true,
+	"OverlayPack":          true,
+	"OverlayDataPack":      true,
+	"Digest":               true,
+	"Lighting":             true,
+	"SpecialFlags":         true,
+	"Waypoints":            true,
+	"Structures":           true,
+	"Units":                true,
+	"Infantry":             true,
+	"Aircraft":             true,
+	"Terrain":              true,
+	"Smudge":               true,
+	"Tubes":                true,
+	"CellTags":             true,
+	"Tags":                 true,
+	"Triggers":             true,
+	"Events":               true,
+	"Actions":              true,
+	"VariableNames":        true,
+	"Ranking":              true,
+	"Houses":               true,
+	"TaskForces":           true,
+	"ScriptTypes":          true,
+	"TeamTypes":            true,
+	"AITriggerTypes":       true,
+	"AITriggerTypesEnable": true,
+}
+
+// mapRegistries 中注册的 section 也属于地图自身，如房屋与 AI 小队的定义
+var mapRegistries = []string{"Houses", "TaskForces", "ScriptTypes", "TeamTypes"}
+
+// IsMapFile 按扩展名判断 filename 是否为地图文件。
+func IsMapFile(filename string) bool {
+	return slices.Contains(MapFileExts, strings.ToLower(filepath.Ext(filename)))
+}
+
+// MapFile 是地图文件中除规则覆盖外的内容。规则覆盖 section 作为 Rules 编辑，
+// 其余 section 按原始字节保存，写回时保持原有位置与格式。
+type MapFile struct {
+	chunks   []mapChunk
+	original *ini.File // 读取时的规则覆盖，未修改的 section 按原样写回
+	crlf     bool
+}
+
+type mapChunk struct {
+	name  string // 为空表示第一个 section 之前的内容
+	raw   []byte
+	rules bool
+}
+
+// LoadMap 读取地图文件，返回地图内容与其中的规则覆盖。
+func LoadMap(r io.Reader) (*MapFile, *Rules, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	m := &MapFile{
+		crlf: bytes.Contains(data, []byte("\r\n")),
+	}
+	cur := mapChunk{}
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if name, ok := sectionHeader(line); ok {
+			m.chunks = append(m.chunks, cur)
+			cur = mapChunk{name: name}
+		}
+		cur.raw = append(cur.raw, line...)
+	}
+	m.chunks = append(m.chunks, cur)
+
+	registered := m.registeredSections()
+	var rulesData bytes.Buffer
+	for i := range m.chunks {
+		c := &m.chunks[i]
+		if c.name == "" || mapSections[c.name] || registered[c.name] {
+			continue
+		}
+		c.rules = true
+		rulesData.Write(c.raw)
+		rulesData.WriteString("\n")
+	}
+	f, err := ini.LoadSources(ini.LoadOptions{
+		KeyValueDelimiters:      "=",
+		SkipUnrecognizableLines: true,
+	}, rulesData.Bytes())
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	m.original = f
+	rules := NewEmptyRules()
+	if err := mergeIni(rules.f, f); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	return m, rules, nil
+}
+
+// Content 返回将 rules 写回地图后的完整内容。已删除的规则 section 不再写出，
+// 新增的规则 section 追加在文件末尾。
+func (m *MapFile) Content(rules *Rules) ([]byte, error) {
+	var buf bytes.Buffer
+	written := make(map[string]bool)
+	for _, c := range m.chunks {
+		if !c.rules {
+			buf.Write(c.raw)
+			continue
+		}
+		if written[c.name] {
+			// 重复的 section 在读取时已经合并
+			continue
+		}
+		written[c.name] = true
+		sec, err := rules.f.GetSection(c.name)
+		if err != nil {
+			continue
+		}
+		if equalSection(sec, m.original.Section(c.name)) && !m.duplicated(c.name) {
+			buf.Write(c.raw)
+			continue
+		}
+		m.writeSection(&buf, sec)
+	}
+	for _, sec := range rules.f.Sections() {
+		if sec.Name() == ini.DefaultSection || written[sec.Name()] {
+			continue
+		}
+		m.writeSection(&buf, sec)
+	}
+	return buf.Bytes(), nil
+}
+
+func (m *MapFile) writeSection(w *bytes.Buffer, sec *ini.Section) {
+	lineBreak := "\n"
+	if m.crlf {
+		lineBreak = "\r\n"
+	}
+	if w.Len() > 0 && !bytes.HasSuffix(w.Bytes(), []byte("\n")) {
+		w.WriteString(lineBreak)
+	}
+	writeComment(w, sec.Comment, lineBreak)
+	w.WriteString("[" + sec.Name() + "]" + lineBreak)
+	for _, key := range sec.Keys() {
+		writeComment(w, key.Comment, lineBreak)
+		w.WriteString(key.Name() + "=" + key.Value() + lineBreak)
+	}
+	w.WriteString(lineBreak)
+}
+
+// writeComment 与 ini 库相同，将注释逐行写在 section 或 key 之前，行尾注释也移到上一行。
+func writeComment(w *bytes.Buffer, comment, lineBreak string) {
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if line[0] != ';' && line[0] != '#' {
+			line = "; " + line
+		}
+		w.WriteString(line + lineBreak)
+	}
+}
+
+// duplicated 返回 name 是否在地图中出现了多次，这样的 section 只能合并后写出。
+func (m *MapFile) duplicated(name string) bool {
+	count := 0
+	for _, c := range m.chunks {
+		if c.rules && c.name == name {
+			count++
+		}
+	}
+	return count > 1
+}
+
+func equalSection(a, b *ini.Section) bool {
+	keysA, keysB := a.Keys(), b.Keys()
+	if len(keysA) != len(keysB) {
+		return false
+	}
+	for i := range keysA {
+		if keysA[i].Name() != keysB[i].Name() || keysA[i].Value() != keysB[i].Value() {
+			return false
+		}
+	}
+	return true
+}
+
+// registeredSections 返回 mapRegistries 中注册的 section 名。
+func (m *MapFile) registeredSections() map[string]bool {
+	names := make(map[string]bool)
+	for _, c := range m.chunks {
+		if c.name == "" || !slices.Contains(mapRegistries, c.name) {
+			continue
+		}
+		f, err := ini.LoadSources(ini.LoadOptions{
+			KeyValueDelimiters:      "=",
+			SkipUnrecognizableLines: true,
+		}, c.raw)
+		if err != nil {
+			continue
+		}
+		for _, key := range f.Section(c.name).Keys() {
+			names[key.Value()] = true
+		}
+	}
+	return names
+}
+
+// sectionHeader 解析 [name] 形式的行。
+func sectionHeader(line []byte) (string, bool) {
+	s := strings.TrimSpace(string(line))
+	if !strings.HasPrefix(s, "[") {
+		return "", false
+	}
+	end := strings.Index(s, "]")
+	if end < 0 {
+		return "", false
+	}
+	return strings.TrimSpace(s[1:end]), true
+}
@@ -0,0 +1,97 @@
+package ra2
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+const testMap = "; Map created with FinalAlert 2\r\n" +
+	"[Basic]\r\nName=Test Map\r\n\r\n" +
+	"[HTNK]\r\nCost=900 ; cheaper rhinos\r\nStrength=400\r\n\r\n" +
+	"[IsoMapPack5]\r\n1=BQAAACAAAAAAAAAAAA\r\n2=AAAAAAAAAAAAAAAAAA\r\n\r\n" +
+	"[TeamTypes]\r\n0=01000000-G\r\n\r\n" +
+	"[01000000-G]\r\nName=Rhinos\r\nMax=5\r\n\r\n" +
+	"[General]\r\nRefinerySmokeOffsetOne=128,0,0\r\n\r\n" +
+	"[Preview]\r\nSize=0,0,100,50\r\n"
+
+func TestLoadMap(t *testing.T) {
+	m, rules, err := LoadMap(bytes.NewReader([]byte(testMap)))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{ini.DefaultSection, "HTNK", "General"}, rules.f.SectionStrings())
+	assert.Equal(t, "900", rules.f.Section("HTNK").Key("Cost").String())
+
+	// 未修改时原样写回
+	assert.Equal(t, testMap, string(lo.Must(m.Content(rules))))
+
+	tests := []struct {
+		name    string
+		prepare func(r *Rules)
+		want    string
+	}{
+		{
+			name: "modify",
+			prepare: func(r *Rules) {
+				r.f.Section("HTNK").Key("Cost").SetValue("1000")
+			},
+			want: "; Map created with FinalAlert 2\r\n" +
+				"[Basic]\r\nName=Test Map\r\n\r\n" +
+				"[HTNK]\r\n; cheaper rhinos\r\nCost=1000\r\nStrength=400\r\n\r\n" +
+				"[IsoMapPack5]\r\n1=BQAAACAAAAAAAAAAAA\r\n2=AAAAAAAAAAAAAAAAAA\r\n\r\n" +
+				"[TeamTypes]\r\n0=01000000-G\r\n\r\n" +
+				"[01000000-G]\r\nName=Rhinos\r\nMax=5\r\n\r\n" +
+				"[General]\r\nRefinerySmokeOffsetOne=128,0,0\r\n\r\n" +
+				"[Preview]\r\nSize=0,0,100,50\r\n",
+		},
+		{
+			// 修改的 section 中其他 key 的注释同样保留
+			name: "modify commented section",
+			prepare: func(r *Rules) {
+				r.f.Section("HTNK").Key("Strength").SetValue("500")
+			},
+			want: "; Map created with FinalAlert 2\r\n" +
+				"[Basic]\r\nName=Test Map\r\n\r\n" +
+				"[HTNK]\r\n; cheaper rhinos\r\nCost=900\r\nStrength=500\r\n\r\n" +
+				"[IsoMapPack5]\r\n1=BQAAACAAAAAAAAAAAA\r\n2=AAAAAAAAAAAAAAAAAA\r\n\r\n" +
+				"[TeamTypes]\r\n0=01000000-G\r\n\r\n" +
+				"[01000000-G]\r\nName=Rhinos\r\nMax=5\r\n\r\n" +
+				"[General]\r\nRefinerySmokeOffsetOne=128,0,0\r\n\r\n" +
+				"[Preview]\r\nSize=0,0,100,50\r\n",
+		},
+		{
+			name: "add and delete",
+			prepare: func(r *Rules) {
+				r.f.DeleteSection("General")
+				r.f.Section("E1").Key("Cost").SetValue("50")
+			},
+			want: "; Map created with FinalAlert 2\r\n" +
+				"[Basic]\r\nName=Test Map\r\n\r\n" +
+				"[HTNK]\r\nCost=900 ; cheaper rhinos\r\nStrength=400\r\n\r\n" +
+				"[IsoMapPack5]\r\n1=BQAAACAAAAAAAAAAAA\r\n2=AAAAAAAAAAAAAAAAAA\r\n\r\n" +
+				"[TeamTypes]\r\n0=01000000-G\r\n\r\n" +
+				"[01000000-G]\r\nName=Rhinos\r\nMax=5\r\n\r\n" +
+				"[Preview]\r\nSize=0,0,100,50\r\n" +
+				"[E1]\r\nCost=50\r\n\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, rules, err := LoadMap(bytes.NewReader([]byte(testMap)))
+			require.NoError(t, err)
+			tt.prepare(rules)
+			assert.Equal(t, tt.want, string(lo.Must(m.Content(rules))))
+		})
+	}
+}
+
+func TestIsMapFile(t *testing.T) {
+	assert.True(t, IsMapFile("a.map"))
+	assert.True(t, IsMapFile("a.YRM"))
+	assert.True(t, IsMapFile("dir/a.mpr"))
+	assert.False(t, IsMapFile("rulesmd.ini"))
+}
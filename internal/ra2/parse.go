@@ -0,0 +1,66 @@
+package ra2
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// ParseError 是 INI 文件的解析错误。Line 从 1 开始，无法确定出错的行时为 0。
+type ParseError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	file := e.File
+	if file == "" {
+		file = "<input>"
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %v", file, e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", file, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// loadIni 解析 data，失败时返回带行号的 *ParseError。
+func loadIni(opts ini.LoadOptions, data []byte) (*ini.File, error) {
+	f, err := ini.LoadSources(opts, data)
+	if err != nil {
+		return nil, &ParseError{
+			Line: errorLine(data, opts.SkipUnrecognizableLines),
+			Err:  err,
+		}
+	}
+	return f, nil
+}
+
+// errorLine 返回 data 中第一个 ini 无法解析的行：未闭合的 section 或缺少 = 的键值行。
+func errorLine(data []byte, skipUnrecognizable bool) int {
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		s := strings.TrimSpace(string(line))
+		if i == 0 {
+			s = strings.TrimPrefix(s, "\ufeff")
+		}
+		if s == "" || s[0] == ';' || s[0] == '#' {
+			continue
+		}
+		if s[0] == '[' {
+			if !strings.Contains(s, "]") {
+				return i + 1
+			}
+			continue
+		}
+		if !skipUnrecognizable && !strings.Contains(s, "=") {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,20 @@
+package ra2
+
+import (
+	"fmt"
+)
+
+// Problem 是数据文件中的一处错误。
+type Problem struct {
+	Section string `json:"section"`
+	Key     string `json:"key,omitempty"`
+	Message string `json:"message"`
+}
+
+func newProblem(section, key, format string, args ...any) Problem {
+	return Problem{
+		Section: section,
+		Key:     key,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
@@ -22,6 +22,29 @@ type Property struct {
 	Desc I18NString `json:"desc"` // 属性描述
 }
 
+// KeyAction 描述覆盖层如何处理一个下层已有的 key。
+type KeyAction string
+
+const (
+	KeyActionSet     KeyAction = ""        // 写入新值
+	KeyActionInherit KeyAction = "inherit" // 从覆盖层移除，沿用下层的值
+	KeyActionClear   KeyAction = "clear"   // 写入空值
+	KeyActionReset   KeyAction = "reset"   // 写入游戏默认值
+)
+
+func NewKeyAction(name string) KeyAction {
+	switch name {
+	case "inherit":
+		return KeyActionInherit
+	case "clear":
+		return KeyActionClear
+	case "reset":
+		return KeyActionReset
+	default:
+		return KeyActionSet
+	}
+}
+
 func parseProperties(sec *ini.Section) []Property {
 	var properties []Property
 	for _, key := range sec.Keys() {
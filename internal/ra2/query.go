@@ -0,0 +1,471 @@
+package ra2
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/ini.v1"
+)
+
+// Query 是解析后的查询表达式，例如：
+//
+//	type:vehicle and Armor=heavy and Speed>6 and Owner=Russians
+//	type:infantry and (Cost>=1000 or not Primary)
+//
+// 比较运算符有 = != < <= > >= 与 ~（包含子串）。= 与 != 对列表类型的值检查是否包含该项，
+// 对布尔与数值类型按值比较，其他类型忽略大小写比较；类型取自 schema。单独的 key 表示该 key 存在，
+// type:<类型> 只匹配该类型的单位。值中有空格或运算符时用双引号括起来。
+type Query struct {
+	src  string
+	expr queryExpr
+	keys []string
+}
+
+// QueryResult 是一个匹配的 section，Values 为查询中涉及的 key 的生效值，未设置时取默认值。
+type QueryResult struct {
+	Section string            `json:"section"`
+	Type    UnitType          `json:"type"`
+	Values  map[string]string `json:"values"`
+}
+
+// ParseQuery 解析查询表达式。
+func ParseQuery(s string) (*Query, error) {
+	tokens, err := scanQuery(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, errors.New("empty query")
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, tok.unexpected()
+	}
+	return &Query{
+		src:  s,
+		expr: expr,
+		keys: p.keys,
+	}, nil
+}
+
+func (q *Query) String() string {
+	return q.src
+}
+
+// Keys 返回查询中涉及的 key，按首次出现的顺序。
+func (q *Query) Keys() []string {
+	return q.keys
+}
+
+// Query 返回 rules 中满足 q 的 section，按文件中的顺序。schema 为 nil 时按值的形式推断类型。
+func (r *Rules) Query(q *Query, schema *Schema) []QueryResult {
+	var results []QueryResult
+	for _, sec := range r.f.Sections() {
+		if sec.Name() == ini.DefaultSection {
+			continue
+		}
+		t := &queryTarget{sec: sec, schema: schema}
+		if unit := r.UnitByName(sec.Name()); unit != nil {
+			t.unitType = unit.Type
+		}
+		if !q.expr.match(t) {
+			continue
+		}
+		values := make(map[string]string)
+		for _, key := range q.keys {
+			if v, ok := t.value(key); ok {
+				values[key] = v
+			}
+		}
+		results = append(results, QueryResult{
+			Section: sec.Name(),
+			Type:    t.unitType,
+			Values:  values,
+		})
+	}
+	return results
+}
+
+// queryTarget 是正在匹配的 section。
+type queryTarget struct {
+	sec      *ini.Section
+	unitType UnitType
+	schema   *Schema
+}
+
+// value 返回 key 的值，没有设置时返回单位的默认值。
+func (t *queryTarget) value(key string) (string, bool) {
+	if t.sec.HasKey(key) {
+		return t.sec.Key(key).Value(), true
+	}
+	if t.unitType != UnitTypeUnknown && t.schema != nil {
+		return t.schema.DefaultValue(t.unitType, key)
+	}
+	return "", false
+}
+
+type valueKind int
+
+const (
+	valueKindString valueKind = iota
+	valueKindBool
+	valueKindNumber
+	valueKindList
+)
+
+// kind 按 schema 中的类型判断 key 的值如何比较，schema 中没有时含逗号的值作为列表。
+func (t *queryTarget) kind(key, value string) valueKind {
+	if t.schema != nil {
+		if flag, ok := t.schema.Flag(t.unitType, t.sec.Name(), key); ok {
+			switch vt := flag.ValueType; {
+			case vt == "boolean":
+				return valueKindBool
+			case vt == "int" || vt == "float":
+				return valueKindNumber
+			case strings.HasPrefix(vt, "vector<") || strings.Contains(vt, "["):
+				return valueKindList
+			default:
+				return valueKindString
+			}
+		}
+	}
+	if strings.Contains(value, ",") {
+		return valueKindList
+	}
+	return valueKindString
+}
+
+type queryExpr interface {
+	match(t *queryTarget) bool
+}
+
+type andExpr struct {
+	left, right queryExpr
+}
+
+func (e *andExpr) match(t *queryTarget) bool {
+	return e.left.match(t) && e.right.match(t)
+}
+
+type orExpr struct {
+	left, right queryExpr
+}
+
+func (e *orExpr) match(t *queryTarget) bool {
+	return e.left.match(t) || e.right.match(t)
+}
+
+type notExpr struct {
+	expr queryExpr
+}
+
+func (e *notExpr) match(t *queryTarget) bool {
+	return !e.expr.match(t)
+}
+
+type typeExpr struct {
+	unitType UnitType
+}
+
+func (e *typeExpr) match(t *queryTarget) bool {
+	return t.unitType == e.unitType
+}
+
+// hasExpr 匹配设置了 key 的 section，不考虑默认值。
+type hasExpr struct {
+	key string
+}
+
+func (e *hasExpr) match(t *queryTarget) bool {
+	return t.sec.HasKey(e.key)
+}
+
+type compareExpr struct {
+	key   string
+	op    string
+	value string
+}
+
+func (e *compareExpr) match(t *queryTarget) bool {
+	v, ok := t.value(e.key)
+	if !ok {
+		return e.op == "!="
+	}
+	kind := t.kind(e.key, v)
+	switch e.op {
+	case "=":
+		return equalValue(kind, v, e.value)
+	case "!=":
+		return !equalValue(kind, v, e.value)
+	case "~":
+		return strings.Contains(strings.ToLower(v), strings.ToLower(e.value))
+	}
+	a, okA := parseNumber(v)
+	b, okB := parseNumber(e.value)
+	if !okA || !okB {
+		return false
+	}
+	switch e.op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	default:
+		return a >= b
+	}
+}
+
+func equalValue(kind valueKind, v, want string) bool {
+	switch kind {
+	case valueKindBool:
+		a, okA := parseBool(v)
+		b, okB := parseBool(want)
+		if okA && okB {
+			return a == b
+		}
+	case valueKindList:
+		for _, item := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(item), want) {
+				return true
+			}
+		}
+	}
+	if strings.EqualFold(strings.TrimSpace(v), want) {
+		return true
+	}
+	a, okA := parseNumber(v)
+	b, okB := parseNumber(want)
+	return okA && okB && a == b
+}
+
+// parseBool 与游戏一致，只看第一个字符。
+func parseBool(s string) (bool, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return false, false
+	}
+	switch s[0] {
+	case 'y', 'Y', 't', 'T', '1':
+		return true, true
+	case 'n', 'N', 'f', 'F', '0':
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// parseNumber 解析整数、小数与百分比，百分比按原数值比较。
+func parseNumber(s string) (float64, bool) {
+	s = strings.TrimSuffix(strings.TrimSpace(s), "%")
+	n, err := strconv.ParseFloat(s, 64)
+	return n, err == nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+)
+
+type queryToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (tok queryToken) unexpected() error {
+	if tok.kind == tokenEOF {
+		return errors.New("unexpected end of query")
+	}
+	return errors.Errorf("unexpected %q at column %d", tok.text, tok.pos+1)
+}
+
+func (tok queryToken) keyword(name string) bool {
+	return tok.kind == tokenWord && strings.EqualFold(tok.text, name)
+}
+
+var queryOps = []string{"!=", "<=", ">=", "=", "<", ">", "~", ":"}
+
+func scanQuery(s string) ([]queryToken, error) {
+	var tokens []queryToken
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, queryToken{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, queryToken{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case c == '"':
+			end := strings.IndexByte(s[i+1:], '"')
+			if end < 0 {
+				return nil, errors.Errorf("unterminated string at column %d", i+1)
+			}
+			tokens = append(tokens, queryToken{kind: tokenString, text: s[i+1 : i+1+end], pos: i})
+			i += end + 2
+		default:
+			if op, ok := scanOp(s[i:]); ok {
+				tokens = append(tokens, queryToken{kind: tokenOp, text: op, pos: i})
+				i += len(op)
+				continue
+			}
+			start := i
+			for i < len(s) && !strings.ContainsRune(" \t\r\n()\"=!<>~:", rune(s[i])) {
+				i++
+			}
+			if i == start {
+				return nil, errors.Errorf("unexpected %q at column %d", s[i:i+1], i+1)
+			}
+			tokens = append(tokens, queryToken{kind: tokenWord, text: s[start:i], pos: start})
+		}
+	}
+	return append(tokens, queryToken{kind: tokenEOF, pos: len(s)}), nil
+}
+
+func scanOp(s string) (string, bool) {
+	for _, op := range queryOps {
+		if strings.HasPrefix(s, op) {
+			return op, true
+		}
+	}
+	return "", false
+}
+
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+	keys   []string
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() queryToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *queryParser) parseOr() (queryExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().keyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (queryExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().keyword("and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &andExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseNot() (queryExpr, error) {
+	if p.peek().keyword("not") {
+		p.next()
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{expr: expr}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (queryExpr, error) {
+	tok := p.next()
+	switch {
+	case tok.kind == tokenLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, closing.unexpected()
+		}
+		return expr, nil
+	case tok.kind != tokenWord || tok.keyword("and") || tok.keyword("or"):
+		return nil, tok.unexpected()
+	}
+
+	op := p.peek()
+	if op.kind != tokenOp {
+		p.addKey(tok.text)
+		return &hasExpr{key: tok.text}, nil
+	}
+	p.next()
+	value := p.next()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, value.unexpected()
+	}
+	if op.text == ":" {
+		return parseFilter(tok, value)
+	}
+	p.addKey(tok.text)
+	return &compareExpr{key: tok.text, op: op.text, value: value.text}, nil
+}
+
+// parseFilter 解析 name:value 形式的过滤条件，目前只有 type。
+func parseFilter(name, value queryToken) (queryExpr, error) {
+	if !strings.EqualFold(name.text, "type") {
+		return nil, errors.Errorf("unknown filter %q at column %d", name.text, name.pos+1)
+	}
+	unitType := NewUnitType(strings.ToLower(value.text))
+	if unitType == UnitTypeUnknown {
+		return nil, errors.Errorf("unknown unit type %q at column %d, expected one of %s",
+			value.text, value.pos+1, unitTypeNames())
+	}
+	return &typeExpr{unitType: unitType}, nil
+}
+
+func unitTypeNames() string {
+	names := make([]string, len(UnitTypes))
+	for i, unitType := range UnitTypes {
+		names[i] = string(unitType)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (p *queryParser) addKey(key string) {
+	if !slices.Contains(p.keys, key) {
+		p.keys = append(p.keys, key)
+	}
+}
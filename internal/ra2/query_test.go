@@ -0,0 +1,114 @@
+package ra2
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testQueryRules = `[InfantryTypes]
+0=E1
+[VehicleTypes]
+0=HTNK
+1=MTNK
+2=APOC
+[E1]
+Cost=200
+Owner=Americans,Russians
+[HTNK]
+Armor=heavy
+Speed=5
+Crusher=yes
+Owner=Russians,Confederation
+[MTNK]
+Armor=heavy
+Speed=7
+Owner=Americans,British
+[APOC]
+Armor=Heavy
+Speed=4
+Crusher=no
+Owner=Russians
+[Colors]
+Red=0,255,255
+`
+
+func newTestQuerySchema() *Schema {
+	return &Schema{Flags: []IniFlag{
+		{Category: "TechnoTypes", Key: "Speed", ValueType: "int", DefaultValue: "0"},
+		{Category: "TechnoTypes", Key: "Cost", ValueType: "int", DefaultValue: "0"},
+		{Category: "TechnoTypes", Key: "Owner", ValueType: "House[32]", DefaultValue: "{}"},
+		{Category: "VehicleTypes", Key: "Crusher", ValueType: "boolean", DefaultValue: "no"},
+		{Category: "ObjectTypes", Key: "Armor", ValueType: "Armor", DefaultValue: "0"},
+		{Category: "General", Section: "[General]", Key: "CrewEscape", ValueType: "float", DefaultValue: "0"},
+	}}
+}
+
+func TestRules_Query(t *testing.T) {
+	rules := newTestRules(testQueryRules)
+	schema := newTestQuerySchema()
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "Armor=heavy and Speed>4 and Owner=Russians", want: []string{"HTNK"}},
+		{query: "type:vehicle and Armor=heavy", want: []string{"HTNK", "MTNK", "APOC"}},
+		{query: "type:infantry or Speed>=7", want: []string{"E1", "MTNK"}},
+		{query: "Owner=russians and not type:infantry", want: []string{"HTNK", "APOC"}},
+		{query: "Owner=Confed", want: []string{}},
+		{query: "Owner~Confed", want: []string{"HTNK"}},
+		// 未设置的 Crusher 按默认值 no 比较
+		{query: "type:vehicle and Crusher=false", want: []string{"MTNK", "APOC"}},
+		{query: "type:vehicle and Crusher!=no", want: []string{"HTNK"}},
+		{query: "type:vehicle and not Crusher", want: []string{"MTNK"}},
+		{query: "(Speed<5 or Cost=200.0) and Owner=Russians", want: []string{"E1", "APOC"}},
+		{query: `Red="0,255,255"`, want: []string{"Colors"}},
+		{query: "Red=255", want: []string{"Colors"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParseQuery(tt.query)
+			require.NoError(t, err)
+			got := lo.Map(rules.Query(q, schema), func(r QueryResult, _ int) string { return r.Section })
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRules_QueryValues(t *testing.T) {
+	rules := newTestRules(testQueryRules)
+	q, err := ParseQuery("type:vehicle and Speed>6 and Crusher=no")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Speed", "Crusher"}, q.Keys())
+	assert.Equal(t, []QueryResult{{
+		Section: "MTNK",
+		Type:    UnitTypeVehicle,
+		Values:  map[string]string{"Speed": "7", "Crusher": "no"},
+	}}, rules.Query(q, newTestQuerySchema()))
+}
+
+func TestParseQuery_Error(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "", want: "empty query"},
+		{query: "Speed>", want: "unexpected end of query"},
+		{query: "Speed>5 Cost", want: `unexpected "Cost" at column 9`},
+		{query: "(Speed>5", want: "unexpected end of query"},
+		{query: "type:tank", want: `unknown unit type "tank" at column 6, expected one of infantry, vehicle, aircraft, building`},
+		{query: "side:soviet", want: `unknown filter "side" at column 1`},
+		{query: `Name="E1`, want: "unterminated string at column 6"},
+		{query: "Speed ! 5", want: `unexpected "!" at column 7`},
+		{query: "and Speed", want: `unexpected "and" at column 1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := ParseQuery(tt.query)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
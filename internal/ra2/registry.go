@@ -0,0 +1,56 @@
+package ra2
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+	"gopkg.in/ini.v1"
+)
+
+// registered 按注册表 list 中的顺序返回注册的名称，如 [TaskForces]、[SoundList]。
+func registered(f *ini.File, list SectionName) []string {
+	sec, err := f.GetSection(string(list))
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, key := range sec.Keys() {
+		names = append(names, key.Value())
+	}
+	return names
+}
+
+// register 以下一个未使用的序号将 name 加入注册表 list。
+func register(f *ini.File, list SectionName, name string) error {
+	sec := f.Section(string(list))
+	next := 0
+	for _, key := range sec.Keys() {
+		if idx, err := strconv.Atoi(key.Name()); err == nil {
+			next = max(next, idx+1)
+		}
+	}
+	if _, err := sec.NewKey(strconv.Itoa(next), name); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// unregister 从注册表 list 中移除 name 并删除其 section。
+func unregister(f *ini.File, list SectionName, name string) error {
+	sec, err := f.GetSection(string(list))
+	if err != nil {
+		return errors.New("not found")
+	}
+	found := false
+	for _, key := range sec.Keys() {
+		if key.Value() == name {
+			sec.DeleteKey(key.Name())
+			found = true
+		}
+	}
+	if !found {
+		return errors.New("not found")
+	}
+	f.DeleteSection(name)
+	return nil
+}
@@ -3,6 +3,8 @@ package ra2
 import (
 	"bytes"
 	"io"
+	"slices"
+	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cast"
@@ -38,6 +40,10 @@ func (s *BaseSetting) Get(key string) string {
 	return ""
 }
 
+func (s *BaseSetting) Has(key string) bool {
+	return s.sec.HasKey(key)
+}
+
 func (s *BaseSetting) Set(key, value string, comment ...string) error {
 	k, err := s.sec.NewKey(key, value)
 	if err != nil {
@@ -117,12 +123,50 @@ func (ut UnitType) Section() SectionName {
 
 type Rules struct {
 	f *ini.File
+
+	mu  sync.Mutex
+	idx *unitIndex // 延迟建立，注册表被整体替换时失效
+}
+
+// unitIndex 是按类型、ID 与注册名查找单位的索引。
+type unitIndex struct {
+	byType map[UnitType][]*Unit
+	byID   map[unitKey]*Unit
+	byName map[string]*Unit
 }
 
+type unitKey struct {
+	Type UnitType
+	ID   int
+}
+
+// NewRules 加载规则文件，格式错误时返回 *ParseError。
 func NewRules(r io.ReadCloser) (*Rules, error) {
-	f, err := ini.LoadSources(ini.LoadOptions{
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	f, err := loadIni(ini.LoadOptions{
 		KeyValueDelimiters: "=",
-	}, r)
+	}, data)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &Rules{
+		f: f,
+	}, nil
+}
+
+// NewArt 加载 art 文件。原版 artmd.ini 中有游戏会忽略的无效行，这里同样跳过。
+func NewArt(r io.ReadCloser) (*Rules, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	f, err := loadIni(ini.LoadOptions{
+		KeyValueDelimiters:      "=",
+		SkipUnrecognizableLines: true,
+	}, data)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -152,6 +196,60 @@ func (r *Rules) Content() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// HasSection 返回是否有名为 name 的 section。
+func (r *Rules) HasSection(name string) bool {
+	return r.f.HasSection(name)
+}
+
+// Value 返回 section 中 key 的值，section 或 key 不存在时返回 false。
+func (r *Rules) Value(section, key string) (string, bool) {
+	sec, err := r.f.GetSection(section)
+	if err != nil || !sec.HasKey(key) {
+		return "", false
+	}
+	return sec.Key(key).Value(), true
+}
+
+// Sections 按文件中的顺序返回所有 section 名，不含默认 section。
+func (r *Rules) Sections() []string {
+	var names []string
+	for _, sec := range r.f.Sections() {
+		if sec.Name() != ini.DefaultSection {
+			names = append(names, sec.Name())
+		}
+	}
+	return names
+}
+
+// Keys 按顺序返回 section 中的 key，section 不存在时返回 nil。
+func (r *Rules) Keys(section string) []string {
+	sec, err := r.f.GetSection(section)
+	if err != nil {
+		return nil
+	}
+	return sec.KeyStrings()
+}
+
+// Comment 返回 section 中 key 的注释，key 为空时返回 section 的注释。
+func (r *Rules) Comment(section, key string) string {
+	sec, err := r.f.GetSection(section)
+	if err != nil {
+		return ""
+	}
+	if key == "" {
+		return sec.Comment
+	}
+	if !sec.HasKey(key) {
+		return ""
+	}
+	return sec.Key(key).Comment
+}
+
+// Registered 按注册表 list 中的顺序返回注册的名称，重复注册的名称会出现多次。
+func (r *Rules) Registered(list SectionName) []string {
+	return registered(r.f, list)
+}
+
 func (r *Rules) Merge(others ...*Rules) (*Rules, error) {
 	f := ini.Empty()
 	if err := mergeIni(f, r.f); err != nil {
@@ -168,37 +266,100 @@ func (r *Rules) Merge(others ...*Rules) (*Rules, error) {
 }
 
 func (r *Rules) Units() []*Unit {
+	idx := r.index()
 	var units []*Unit
-	units = append(units, r.UnitsByType(UnitTypeInfantry)...)
-	units = append(units, r.UnitsByType(UnitTypeVehicle)...)
-	units = append(units, r.UnitsByType(UnitTypeAircraft)...)
-	units = append(units, r.UnitsByType(UnitTypeBuilding)...)
+	for _, unitType := range UnitTypes {
+		units = append(units, idx.byType[unitType]...)
+	}
 	return units
 }
 
 func (r *Rules) UnitsByType(unitType UnitType) []*Unit {
-	var units []*Unit
-	for _, key := range r.f.Section(string(unitType.Section())).Keys() {
-		id := cast.ToInt(key.Name())
-		name := key.Value()
-		units = append(units, &Unit{
-			BaseSetting: BaseSetting{sec: r.f.Section(name)},
+	return slices.Clone(r.index().byType[unitType])
+}
 
-			Type: unitType,
-			ID:   id,
-			Name: name,
-		})
+func (r *Rules) GetUnit(unitType UnitType, unitID int) *Unit {
+	return r.index().byID[unitKey{unitType, unitID}]
+}
+
+// UnitByName 按注册名查找单位，如 E1。
+func (r *Rules) UnitByName(name string) *Unit {
+	return r.index().byName[name]
+}
+
+// UnitSection 将已有的 section name 作为 unitType 类型的单位返回，section 不存在时返回 nil。
+// 覆盖层（如地图）只写出要覆盖的单位 section，不在注册表中登记，GetUnit 找不到这些单位。
+func (r *Rules) UnitSection(unitType UnitType, unitID int, name string) *Unit {
+	sec, err := r.f.GetSection(name)
+	if err != nil {
+		return nil
+	}
+	return &Unit{
+		BaseSetting: BaseSetting{sec: sec},
+
+		Type: unitType,
+		ID:   unitID,
+		Name: name,
 	}
-	return units
 }
 
-func (r *Rules) GetUnit(unitType UnitType, unitID int) *Unit {
-	for _, unit := range r.Units() {
-		if unit.Type == unitType && unit.ID == unitID {
-			return unit
+// index 返回单位索引，尚未建立时从注册表建立。
+func (r *Rules) index() *unitIndex {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.idx != nil {
+		return r.idx
+	}
+	idx := &unitIndex{
+		byType: make(map[UnitType][]*Unit),
+		byID:   make(map[unitKey]*Unit),
+		byName: make(map[string]*Unit),
+	}
+	for _, unitType := range UnitTypes {
+		defSec, err := r.f.GetSection(string(unitType.Section()))
+		if err != nil {
+			continue
+		}
+		for _, key := range defSec.Keys() {
+			name := key.Value()
+			idx.add(&Unit{
+				BaseSetting: BaseSetting{sec: r.f.Section(name)},
+
+				Type: unitType,
+				ID:   cast.ToInt(key.Name()),
+				Name: name,
+			})
 		}
 	}
-	return nil
+	r.idx = idx
+	return idx
+}
+
+// invalidate 使单位索引失效，直接修改注册表或整体替换文件后需要调用。
+func (r *Rules) invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.idx = nil
+}
+
+func (idx *unitIndex) add(unit *Unit) {
+	idx.byType[unit.Type] = append(idx.byType[unit.Type], unit)
+	// 重复注册时与游戏一致，以第一次出现的为准
+	key := unitKey{unit.Type, unit.ID}
+	if _, ok := idx.byID[key]; !ok {
+		idx.byID[key] = unit
+	}
+	if _, ok := idx.byName[unit.Name]; !ok {
+		idx.byName[unit.Name] = unit
+	}
+}
+
+func (idx *unitIndex) remove(unit *Unit) {
+	idx.byType[unit.Type] = slices.DeleteFunc(idx.byType[unit.Type], func(u *Unit) bool {
+		return u == unit
+	})
+	delete(idx.byID, unitKey{unit.Type, unit.ID})
+	delete(idx.byName, unit.Name)
 }
 
 func (r *Rules) AddUnit(unitType UnitType, unitID int, unitName string, properties []Property) (*Unit, error) {
@@ -231,6 +392,7 @@ func (r *Rules) AddUnit(unitType UnitType, unitID int, unitName string, properti
 			return nil, errors.WithStack(err)
 		}
 	}
+	r.index().add(unit)
 	return unit, nil
 }
 
@@ -242,6 +404,7 @@ func (r *Rules) DelUnit(unitType UnitType, unitID int) error {
 	r.f.DeleteSection(unit.Name)
 	defSec := r.f.Section(string(unitType.Section()))
 	defSec.DeleteKey(cast.ToString(unitID))
+	r.index().remove(unit)
 	return nil
 }
 
@@ -251,6 +414,9 @@ func mergeIni(dst, src *ini.File) error {
 		if err != nil {
 			return errors.WithStack(err)
 		}
+		if sec.Comment != "" {
+			newSec.Comment = sec.Comment
+		}
 		for _, key := range sec.Keys() {
 			newKey, err := newSec.NewKey(key.Name(), key.Value())
 			if err != nil {
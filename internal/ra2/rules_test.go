@@ -1,7 +1,9 @@
 package ra2
 
 import (
+	"io"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/samber/lo"
@@ -66,3 +68,79 @@ func TestRules_Merge(t *testing.T) {
 		})
 	}
 }
+
+func TestRules_UnitIndex(t *testing.T) {
+	r := newTestRules("[InfantryTypes]\n0=E1\n1=E2\n[VehicleTypes]\n0=HTNK\n[E1]\nCost=100")
+
+	assert.Equal(t, "E2", r.GetUnit(UnitTypeInfantry, 1).Name)
+	assert.Equal(t, "HTNK", r.GetUnit(UnitTypeVehicle, 0).Name)
+	assert.Nil(t, r.GetUnit(UnitTypeVehicle, 1))
+	assert.Equal(t, "100", r.UnitByName("E1").Get("Cost"))
+
+	snap := lo.Must(r.Snapshot(string(SectionNameInfantry), "E3"))
+	_, err := r.AddUnit(UnitTypeInfantry, 2, "E3", []Property{{Key: "Cost", Value: "300"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "E3", r.GetUnit(UnitTypeInfantry, 2).Name)
+	assert.Equal(t, []string{"E1", "E2", "E3", "HTNK"}, lo.Map(r.Units(), func(u *Unit, _ int) string { return u.Name }))
+
+	assert.NoError(t, r.DelUnit(UnitTypeInfantry, 1))
+	assert.Nil(t, r.GetUnit(UnitTypeInfantry, 1))
+	assert.Nil(t, r.UnitByName("E2"))
+
+	assert.NoError(t, r.Restore(snap))
+	assert.Equal(t, "E2", r.GetUnit(UnitTypeInfantry, 1).Name)
+	assert.Nil(t, r.UnitByName("E3"))
+}
+
+func TestNewRules_ParseError(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    int
+	}{
+		{name: "missing delimiter", content: "[E1]\nCost=100\nStrength\n", line: 3},
+		{name: "unclosed section", content: "; comment\n\n[E1\nCost=100\n", line: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRules(io.NopCloser(strings.NewReader(tt.content)))
+			var parseErr *ParseError
+			if assert.ErrorAs(t, err, &parseErr) {
+				assert.Equal(t, tt.line, parseErr.Line)
+			}
+		})
+	}
+}
+
+func loadTestRules(b *testing.B) *Rules {
+	f, err := os.Open("../../data/rulesmd.ini")
+	if err != nil {
+		b.Fatalf("failed to open rules file: %v", err)
+	}
+	defer f.Close()
+	rules, err := NewRules(f)
+	if err != nil {
+		b.Fatalf("failed to parse file: %v", err)
+	}
+	return rules
+}
+
+func BenchmarkRules_GetUnit(b *testing.B) {
+	rules := loadTestRules(b)
+	units := rules.Units()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		unit := units[i%len(units)]
+		if rules.GetUnit(unit.Type, unit.ID) == nil {
+			b.Fatalf("unit %s not found", unit.Name)
+		}
+	}
+}
+
+func BenchmarkRules_Units(b *testing.B) {
+	rules := loadTestRules(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rules.Units()
+	}
+}
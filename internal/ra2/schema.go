@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"io"
 	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -22,6 +24,7 @@ type IniFlag struct {
 	DefaultValue string `json:"default_value"`
 	AddsToList   string `json:"adds_to_list"`
 	Desc         string `json:"desc"`
+	Engine       string `json:"engine,omitempty"` // 需要的引擎扩展，如 ares，原版的 flag 为空
 }
 
 func LoadSchema(r io.Reader) (*Schema, error) {
@@ -32,6 +35,13 @@ func LoadSchema(r io.Reader) (*Schema, error) {
 	return &schema, nil
 }
 
+// Extend 追加 others 中的 flag，例如 Ares 等引擎扩展或 mod 自定义的 key。
+func (s *Schema) Extend(others ...*Schema) {
+	for _, other := range others {
+		s.Flags = append(s.Flags, other.Flags...)
+	}
+}
+
 func (s *Schema) getFlags(category string) []Property {
 	var res []Property
 	for _, flag := range s.Flags {
@@ -49,15 +59,151 @@ func (s *Schema) getFlags(category string) []Property {
 }
 
 func (s *Schema) ListAvailableUnitProperties(unitType UnitType) []Property {
+	var res []Property
+	for _, category := range unitCategories(unitType) {
+		res = append(res, s.getFlags(category)...)
+	}
+	return res
+}
+
+// DefaultValue 返回 key 在 unitType 下的游戏默认值，schema 中没有记录默认值时返回 false。
+func (s *Schema) DefaultValue(unitType UnitType, key string) (string, bool) {
+	categories := unitCategories(unitType)
+	for _, flag := range s.Flags {
+		if flag.Key != key || !slices.Contains(categories, flag.Category) {
+			continue
+		}
+		switch v := flag.DefaultValue; {
+		case v == "?" || v == "":
+			return "", false
+		case v == "{}" || v == `""`:
+			return "", true
+		case strings.HasPrefix(v, "{") && strings.HasSuffix(v, "}"):
+			return strings.ReplaceAll(strings.Trim(v, "{}"), ";", ","), true
+		default:
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// Flag 返回 section 中 key 的定义。单位按 unitType 的类别查找，其他 section 按 schema 中记录的
+// section 查找，如 [General]。与游戏读取 INI 相同，key 不区分大小写。
+func (s *Schema) Flag(unitType UnitType, section, key string) (IniFlag, bool) {
+	categories := unitCategories(unitType)
+	for _, flag := range s.Flags {
+		if !strings.EqualFold(flag.Key, key) {
+			continue
+		}
+		if categories != nil && slices.Contains(categories, flag.Category) ||
+			categories == nil && flag.Section == "["+section+"]" {
+			return flag, true
+		}
+	}
+	return IniFlag{}, false
+}
+
+// ValidateValue 按 schema 中记录的类型检查 section 中 key 的值，unitType 为 section 的单位类型。
+// schema 中没有记录该 key 或无法检查其类型时不报错。
+func (s *Schema) ValidateValue(unitType UnitType, section, key, value string) error {
+	flag, ok := s.Flag(unitType, section, key)
+	if !ok {
+		return nil
+	}
+	valid := true
+	switch flag.ValueType {
+	case "boolean":
+		_, valid = parseBool(value)
+	case "int":
+		_, err := strconv.Atoi(strings.TrimSpace(value))
+		valid = err == nil
+	case "float":
+		_, valid = parseNumber(value)
+	case "vector<int>":
+		for _, item := range strings.Split(value, ",") {
+			if _, err := strconv.Atoi(strings.TrimSpace(item)); err != nil {
+				valid = false
+			}
+		}
+	}
+	if !valid {
+		return errors.Errorf("%q is not a valid %s", value, flag.ValueType)
+	}
+	return nil
+}
+
+// maxSuggestions 是 Suggest 最多返回的候选数
+const maxSuggestions = 3
+
+// Suggest 返回与 section 中未知的 key 拼写相近的已知 key，按编辑距离从近到远排列，不区分大小写。
+// 候选范围与 Flag 相同，key 已知时（包括只有大小写不同）返回 nil。
+func (s *Schema) Suggest(unitType UnitType, section, key string) []string {
+	if _, ok := s.Flag(unitType, section, key); ok {
+		return nil
+	}
+	categories := unitCategories(unitType)
+	limit := max(1, min(3, len(key)/3))
+	distances := make(map[string]int)
+	for _, flag := range s.Flags {
+		if categories != nil && !slices.Contains(categories, flag.Category) ||
+			categories == nil && flag.Section != "["+section+"]" {
+			continue
+		}
+		if _, ok := distances[flag.Key]; ok {
+			continue
+		}
+		if d := editDistance(strings.ToLower(key), strings.ToLower(flag.Key)); d <= limit {
+			distances[flag.Key] = d
+		}
+	}
+	keys := make([]string, 0, len(distances))
+	for k := range distances {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		if distances[a] != distances[b] {
+			return distances[a] - distances[b]
+		}
+		return strings.Compare(a, b)
+	})
+	if len(keys) > maxSuggestions {
+		keys = keys[:maxSuggestions]
+	}
+	return keys
+}
+
+// editDistance 返回 a 与 b 的 Levenshtein 距离。
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func unitCategories(unitType UnitType) []string {
 	switch unitType {
 	case UnitTypeInfantry:
-		return slices.Concat(s.getFlags("AbstractTypes"), s.getFlags("ObjectTypes"), s.getFlags("TechnoTypes"), s.getFlags("InfantryTypes"))
+		return []string{"AbstractTypes", "ObjectTypes", "TechnoTypes", "InfantryTypes"}
 	case UnitTypeVehicle:
-		return slices.Concat(s.getFlags("AbstractTypes"), s.getFlags("ObjectTypes"), s.getFlags("TechnoTypes"), s.getFlags("VehicleTypes"))
+		return []string{"AbstractTypes", "ObjectTypes", "TechnoTypes", "VehicleTypes"}
 	case UnitTypeAircraft:
-		return slices.Concat(s.getFlags("AbstractTypes"), s.getFlags("ObjectTypes"), s.getFlags("TechnoTypes"), s.getFlags("AircraftTypes"))
+		return []string{"AbstractTypes", "ObjectTypes", "TechnoTypes", "AircraftTypes"}
 	case UnitTypeBuilding:
-		return slices.Concat(s.getFlags("AbstractTypes"), s.getFlags("ObjectTypes"), s.getFlags("TechnoTypes"), s.getFlags("BuildingTypes"))
+		return []string{"AbstractTypes", "ObjectTypes", "TechnoTypes", "BuildingTypes"}
 	default:
 		return nil
 	}
@@ -0,0 +1,108 @@
+package ra2
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func loadTestSchema(t testing.TB) *Schema {
+	schemaFile, err := os.Open("../../data/schema/schema.json")
+	if err != nil {
+		t.Fatalf("failed to open schema file: %v", err)
+	}
+	defer schemaFile.Close()
+	schema, err := LoadSchema(schemaFile)
+	if err != nil {
+		t.Fatalf("failed to load schema: %v", err)
+	}
+	return schema
+}
+
+func TestSchema_DefaultValue(t *testing.T) {
+	schema := loadTestSchema(t)
+
+	tests := []struct {
+		name     string
+		unitType UnitType
+		key      string
+		want     string
+		wantOK   bool
+	}{
+		{name: "int", unitType: UnitTypeVehicle, key: "Cost", want: "0", wantOK: true},
+		{name: "empty list", unitType: UnitTypeInfantry, key: "VoiceSelect", want: "", wantOK: true},
+		{name: "xyz", unitType: UnitTypeVehicle, key: "DamageSmokeOffset", want: "0,0,0", wantOK: true},
+		{name: "unknown key", unitType: UnitTypeVehicle, key: "NoSuchKey", wantOK: false},
+		{name: "unknown type", unitType: UnitTypeUnknown, key: "Cost", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := schema.DefaultValue(tt.unitType, tt.key)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSchema_ValidateValue(t *testing.T) {
+	schema := loadTestSchema(t)
+
+	tests := []struct {
+		name     string
+		unitType UnitType
+		section  string
+		key      string
+		value    string
+		wantErr  string
+	}{
+		{name: "int", unitType: UnitTypeVehicle, section: "HTNK", key: "Cost", value: "900"},
+		{name: "bad int", unitType: UnitTypeVehicle, section: "HTNK", key: "Cost", value: "9.5", wantErr: `"9.5" is not a valid int`},
+		{name: "case insensitive key", unitType: UnitTypeVehicle, section: "HTNK", key: "cOST", value: "9.5", wantErr: `"9.5" is not a valid int`},
+		{name: "bool", unitType: UnitTypeVehicle, section: "HTNK", key: "Crusher", value: "yes"},
+		{name: "bad bool", unitType: UnitTypeVehicle, section: "HTNK", key: "Crusher", value: "maybe", wantErr: `"maybe" is not a valid boolean`},
+		{name: "unchecked type", unitType: UnitTypeVehicle, section: "HTNK", key: "Armor", value: "heavy"},
+		{name: "unknown key", unitType: UnitTypeVehicle, section: "HTNK", key: "NoSuchKey", value: "x"},
+		{name: "general", section: "General", key: "BuildSpeed", value: "fast", wantErr: `"fast" is not a valid float`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.ValidateValue(tt.unitType, tt.section, tt.key, tt.value)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchema_Suggest(t *testing.T) {
+	schema := loadTestSchema(t)
+
+	tests := []struct {
+		name     string
+		unitType UnitType
+		section  string
+		key      string
+		want     []string
+	}{
+		{name: "transposed", unitType: UnitTypeVehicle, section: "HTNK", key: "Strenght", want: []string{"Strength"}},
+		{name: "case", unitType: UnitTypeVehicle, section: "HTNK", key: "COST"},
+		{name: "known key", unitType: UnitTypeVehicle, section: "HTNK", key: "Cost"},
+		{name: "nothing close", unitType: UnitTypeVehicle, section: "HTNK", key: "Zzzzzzzz", want: []string{}},
+		{name: "general", section: "General", key: "BuildSped", want: []string{"BuildSpeed"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, schema.Suggest(tt.unitType, tt.section, tt.key))
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("cost", "cost"))
+	assert.Equal(t, 2, editDistance("strenght", "strength"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+	assert.Equal(t, 4, editDistance("", "cost"))
+}
@@ -0,0 +1,87 @@
+package ra2
+
+import (
+	"github.com/pkg/errors"
+	"gopkg.in/ini.v1"
+)
+
+// Snapshot 是 Rules 在某一时刻的副本，用于撤销与重做。
+type Snapshot struct {
+	// 为 nil 时表示整个文件的快照
+	sections map[string]*ini.Section
+	f        *ini.File
+}
+
+// Snapshot 复制 sections 的当前内容，不传 sections 时复制整个文件。
+// 快照中会记录不存在的 section，恢复时将其删除。
+func (r *Rules) Snapshot(sections ...string) (*Snapshot, error) {
+	f := ini.Empty()
+	if len(sections) == 0 {
+		if err := mergeIni(f, r.f); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return &Snapshot{f: f}, nil
+	}
+
+	snap := &Snapshot{
+		sections: make(map[string]*ini.Section),
+		f:        f,
+	}
+	for _, name := range sections {
+		sec, err := r.f.GetSection(name)
+		if err != nil {
+			snap.sections[name] = nil
+			continue
+		}
+		newSec, err := f.NewSection(name)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if err := copySection(newSec, sec); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		snap.sections[name] = newSec
+	}
+	return snap, nil
+}
+
+// Restore 将 Rules 恢复到快照时的内容。
+func (r *Rules) Restore(snap *Snapshot) error {
+	defer r.invalidate()
+	if snap.sections == nil {
+		f := ini.Empty()
+		if err := mergeIni(f, snap.f); err != nil {
+			return errors.WithStack(err)
+		}
+		r.f = f
+		return nil
+	}
+
+	for name, saved := range snap.sections {
+		if saved == nil {
+			r.f.DeleteSection(name)
+			continue
+		}
+		// 原地替换 key，保持 section 在文件中的位置
+		sec := r.f.Section(name)
+		for _, key := range sec.KeyStrings() {
+			sec.DeleteKey(key)
+		}
+		if err := copySection(sec, saved); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func copySection(dst, src *ini.Section) error {
+	dst.Comment = src.Comment
+	for _, key := range src.Keys() {
+		newKey, err := dst.NewKey(key.Name(), key.Value())
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		newKey.Comment = key.Comment
+	}
+	return nil
+}
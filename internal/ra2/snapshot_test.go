@@ -0,0 +1,71 @@
+package ra2
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/ini.v1"
+)
+
+func TestRules_Snapshot(t *testing.T) {
+	const content = "[VehicleTypes]\n1=HTNK\n[HTNK]\nCost=900\n[MTNK]\nCost=700"
+
+	tests := []struct {
+		name     string
+		sections []string
+		modify   func(r *Rules)
+		// 被删除后恢复的 section 会追加到文件末尾
+		keepOrder bool
+	}{
+		{
+			name: "whole file",
+			modify: func(r *Rules) {
+				r.f.DeleteSection("MTNK")
+				r.f.Section("HTNK").Key("Cost").SetValue("1000")
+			},
+			keepOrder: true,
+		},
+		{
+			name:     "modify section",
+			sections: []string{"HTNK"},
+			modify: func(r *Rules) {
+				r.f.Section("HTNK").Key("Cost").SetValue("1000")
+				r.f.Section("HTNK").Key("Speed").SetValue("6")
+			},
+			keepOrder: true,
+		},
+		{
+			name:     "add unit",
+			sections: []string{"VehicleTypes", "APOC"},
+			modify: func(r *Rules) {
+				_, err := r.AddUnit(UnitTypeVehicle, 2, "APOC", []Property{{Key: "Cost", Value: "1750"}})
+				assert.NoError(t, err)
+			},
+			keepOrder: true,
+		},
+		{
+			name:     "delete unit",
+			sections: []string{"VehicleTypes", "HTNK"},
+			modify: func(r *Rules) {
+				assert.NoError(t, r.DelUnit(UnitTypeVehicle, 1))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Rules{f: lo.Must(ini.Load([]byte(content)))}
+			snap, err := r.Snapshot(tt.sections...)
+			assert.NoError(t, err)
+
+			tt.modify(r)
+			assert.False(t, compareIni(r.f, lo.Must(ini.Load([]byte(content)))))
+
+			assert.NoError(t, r.Restore(snap))
+			assert.True(t, compareIni(r.f, lo.Must(ini.Load([]byte(content)))))
+			if tt.keepOrder {
+				assert.Equal(t, []string{ini.DefaultSection, "VehicleTypes", "HTNK", "MTNK"}, r.f.SectionStrings())
+			}
+		})
+	}
+}
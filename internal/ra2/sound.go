@@ -0,0 +1,172 @@
+package ra2
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/ini.v1"
+)
+
+// soundmd.ini 与 evamd.ini 中的注册表
+const (
+	SectionNameSoundList  SectionName = "SoundList"
+	SectionNameDialogList SectionName = "DialogList"
+)
+
+// SoundEntry 是一个音效或 EVA 语音的定义，如 [GISelect] 中的 Sounds=、Control= 等。
+type SoundEntry struct {
+	Name       string     `json:"name"`
+	Properties []Property `json:"properties"`
+}
+
+// SoundList 以注册表加同名 section 的结构读写 soundmd.ini 或 evamd.ini。
+type SoundList struct {
+	f    *ini.File
+	list SectionName
+}
+
+// NewSoundList 读写 soundmd.ini 中的 [SoundList]。
+func NewSoundList(r *Rules) *SoundList {
+	return &SoundList{
+		f:    r.f,
+		list: SectionNameSoundList,
+	}
+}
+
+// NewEVAList 读写 evamd.ini 中的 [DialogList]。
+func NewEVAList(r *Rules) *SoundList {
+	return &SoundList{
+		f:    r.f,
+		list: SectionNameDialogList,
+	}
+}
+
+// Names 按注册顺序返回所有名称。
+func (l *SoundList) Names() []string {
+	return registered(l.f, l.list)
+}
+
+// Has 返回 name 是否已注册，与游戏一致不区分大小写。
+func (l *SoundList) Has(name string) bool {
+	return slices.ContainsFunc(l.Names(), func(n string) bool {
+		return strings.EqualFold(n, name)
+	})
+}
+
+func (l *SoundList) Entries() []*SoundEntry {
+	var entries []*SoundEntry
+	for _, name := range l.Names() {
+		entries = append(entries, l.Entry(name))
+	}
+	return entries
+}
+
+// Entry 返回 name 的定义，未注册时返回 nil。
+func (l *SoundList) Entry(name string) *SoundEntry {
+	if !slices.Contains(l.Names(), name) {
+		return nil
+	}
+	entry := &SoundEntry{Name: name}
+	if sec, err := l.f.GetSection(name); err == nil {
+		entry.Properties = parseProperties(sec)
+	}
+	return entry
+}
+
+// Set 注册并写入 entry，不在 Properties 中的 key 会被删除。
+func (l *SoundList) Set(entry *SoundEntry) error {
+	if entry.Name == "" {
+		return errors.New("name is empty")
+	}
+	if !slices.Contains(l.Names(), entry.Name) {
+		if err := register(l.f, l.list, entry.Name); err != nil {
+			return err
+		}
+	}
+	sec := l.f.Section(entry.Name)
+	keep := make(map[string]bool)
+	for _, prop := range entry.Properties {
+		keep[prop.Key] = true
+	}
+	for _, key := range sec.KeyStrings() {
+		if !keep[key] {
+			sec.DeleteKey(key)
+		}
+	}
+	for _, prop := range entry.Properties {
+		k, err := sec.NewKey(prop.Key, prop.Value)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		k.Comment = prop.Comment
+	}
+	return nil
+}
+
+func (l *SoundList) Del(name string) error {
+	return unregister(l.f, l.list, name)
+}
+
+// ValidateSounds 检查 rules 中 Sound 类型的 flag 是否引用了 sounds 中定义的音效，
+// EVAVoice 类型的 flag 是否引用了 eva 中定义的语音。
+// 列表为空时视为未加载对应文件，不做检查。
+func ValidateSounds(rules *Rules, schema *Schema, sounds, eva *SoundList) []Problem {
+	var problems []Problem
+	for _, flag := range schema.Flags {
+		var (
+			list *SoundList
+			kind string
+		)
+		switch {
+		case strings.Contains(flag.ValueType, "Sound"):
+			list, kind = sounds, "sound"
+		case flag.ValueType == "EVAVoice":
+			list, kind = eva, "eva event"
+		default:
+			continue
+		}
+		if list == nil || len(list.Names()) == 0 {
+			continue
+		}
+		vector := strings.HasPrefix(flag.ValueType, "vector<")
+		for _, sec := range flagSections(rules, flag) {
+			if !sec.HasKey(flag.Key) {
+				continue
+			}
+			values := []string{sec.Key(flag.Key).String()}
+			if vector {
+				values = strings.Split(values[0], ",")
+			}
+			for _, value := range values {
+				value = strings.TrimSpace(value)
+				if value == "" || strings.EqualFold(value, "none") || value == NoneValue {
+					continue
+				}
+				if !list.Has(value) {
+					problems = append(problems, newProblem(sec.Name(), flag.Key, "%s %s is not defined", kind, value))
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// flagSections 返回 rules 中 flag 适用的 section：单位类 flag 适用于对应类别的所有单位，
+// 其他 flag 适用于 schema 中记录的 section，如 [CrateRules]。
+func flagSections(rules *Rules, flag IniFlag) []*ini.Section {
+	if strings.HasPrefix(flag.Section, "[") {
+		sec, err := rules.f.GetSection(strings.Trim(flag.Section, "[]"))
+		if err != nil {
+			return nil
+		}
+		return []*ini.Section{sec}
+	}
+	var sections []*ini.Section
+	for _, unit := range rules.Units() {
+		if slices.Contains(unitCategories(unit.Type), flag.Category) {
+			sections = append(sections, unit.sec)
+		}
+	}
+	return sections
+}
@@ -0,0 +1,83 @@
+package ra2
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func newTestRules(content string) *Rules {
+	return &Rules{f: lo.Must(ini.LoadSources(ini.LoadOptions{KeyValueDelimiters: "="}, []byte(content)))}
+}
+
+func TestSoundList(t *testing.T) {
+	sounds := NewSoundList(newTestRules("[SoundList]\n0=GISelect\n1=GIDie\n[GISelect]\nSounds=igisea igiseb\nControl=random\n[GIDie]\nSounds=igidia"))
+
+	assert.Equal(t, []string{"GISelect", "GIDie"}, sounds.Names())
+	assert.True(t, sounds.Has("giselect"))
+	assert.False(t, sounds.Has("GIMove"))
+	assert.Equal(t, []Property{
+		{Key: "Sounds", Value: "igisea igiseb"},
+		{Key: "Control", Value: "random"},
+	}, sounds.Entry("GISelect").Properties)
+	assert.Nil(t, sounds.Entry("GIMove"))
+
+	require.NoError(t, sounds.Set(&SoundEntry{
+		Name:       "GISelect",
+		Properties: []Property{{Key: "Sounds", Value: "igisea"}},
+	}))
+	assert.Equal(t, []Property{{Key: "Sounds", Value: "igisea"}}, sounds.Entry("GISelect").Properties)
+
+	require.NoError(t, sounds.Set(&SoundEntry{Name: "GIMove"}))
+	assert.Equal(t, []string{"GISelect", "GIDie", "GIMove"}, sounds.Names())
+
+	require.NoError(t, sounds.Del("GIDie"))
+	assert.Equal(t, []string{"GISelect", "GIMove"}, sounds.Names())
+	assert.Error(t, sounds.Del("GIDie"))
+}
+
+func TestValidateSounds(t *testing.T) {
+	schema := loadTestSchema(t)
+	sounds := NewSoundList(newTestRules("[SoundList]\n0=GISelect\n1=GIDie\n2=HealCrate"))
+	eva := NewEVAList(newTestRules("[DialogList]\n0=EVA_StructureCaptured"))
+
+	tests := []struct {
+		name  string
+		rules string
+		eva   *SoundList
+		want  []Problem
+	}{
+		{
+			name:  "valid",
+			rules: "[InfantryTypes]\n0=E1\n[E1]\nVoiceSelect=GISelect,giselect\nDieSound=GIDie\nCrushSound=none",
+		},
+		{
+			name:  "undefined vector value",
+			rules: "[InfantryTypes]\n0=E1\n[E1]\nVoiceSelect=GISelect, GIMove",
+			want:  []Problem{{Section: "E1", Key: "VoiceSelect", Message: "sound GIMove is not defined"}},
+		},
+		{
+			name:  "undefined global value",
+			rules: "[CrateRules]\nHealCrateSound=Heal",
+			want:  []Problem{{Section: "CrateRules", Key: "HealCrateSound", Message: "sound Heal is not defined"}},
+		},
+		{
+			name:  "eva",
+			rules: "[BuildingTypes]\n0=GAPOWR\n[GAPOWR]\nCaptureEvaEvent=EVA_Captured",
+			eva:   eva,
+			want:  []Problem{{Section: "GAPOWR", Key: "CaptureEvaEvent", Message: "eva event EVA_Captured is not defined"}},
+		},
+		{
+			name:  "eva not loaded",
+			rules: "[BuildingTypes]\n0=GAPOWR\n[GAPOWR]\nCaptureEvaEvent=EVA_Captured",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidateSounds(newTestRules(tt.rules), schema, sounds, tt.eva))
+		})
+	}
+}
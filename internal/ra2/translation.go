@@ -40,3 +40,12 @@ func (t *Translation) Get(key string) string {
 	}
 	return k.String()
 }
+
+// Lookup 返回 key 对应的文本，不存在时返回 false。
+func (t *Translation) Lookup(key string) (string, bool) {
+	k, err := t.sec.GetKey(key)
+	if err != nil {
+		return "", false
+	}
+	return k.String(), true
+}
@@ -0,0 +1,118 @@
+package ra2
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ArmorTypes 是弹头 Verses 中各项依次对应的护甲类型。
+var ArmorTypes = []string{
+	"none", "flak", "plate", "light", "medium", "heavy",
+	"wood", "steel", "concrete", "special_1", "special_2",
+}
+
+// IsArmorType 返回 name 是否为 ArmorTypes 中的护甲类型，不区分大小写。
+func IsArmorType(name string) bool {
+	return slices.ContainsFunc(ArmorTypes, func(t string) bool { return strings.EqualFold(t, name) })
+}
+
+// weaponSlots 是单位上固定名称的武器槽位，WeaponCount 指定的 Weapon1..N 排在其后。
+var weaponSlots = []string{
+	"Primary", "Secondary", "ElitePrimary", "EliteSecondary", "OccupyWeapon", "EliteOccupyWeapon",
+}
+
+// Weapon 是单位某个槽位上的武器，包含武器与其弹头中和伤害有关的属性。
+type Weapon struct {
+	Slot       string    `json:"slot"` // 如 Primary、Weapon1
+	Name       string    `json:"name"` // 武器 section 名，如 120mm
+	Damage     int       `json:"damage"`
+	ROF        int       `json:"rof"`   // 两次开火之间的帧数
+	Range      float64   `json:"range"` // 以格为单位
+	Burst      int       `json:"burst"`
+	Projectile string    `json:"projectile"`
+	Warhead    string    `json:"warhead"`
+	Verses     []float64 `json:"verses"` // 对 ArmorTypes 中各护甲的伤害倍率，1 表示 100%
+}
+
+// Weapons 按槽位顺序返回 unit 的武器，未设置的槽位被省略。
+func (r *Rules) Weapons(unit *Unit) []Weapon {
+	slots := weaponSlots
+	if n, err := strconv.Atoi(strings.TrimSpace(unit.Get("WeaponCount"))); err == nil {
+		slots = append(slots[:len(slots):len(slots)], numberedSlots("Weapon", n)...)
+	}
+	var weapons []Weapon
+	for _, slot := range slots {
+		name := strings.TrimSpace(unit.Get(slot))
+		if name == "" || strings.EqualFold(name, "none") {
+			continue
+		}
+		weapon := r.Weapon(name)
+		weapon.Slot = slot
+		weapons = append(weapons, weapon)
+	}
+	return weapons
+}
+
+// Weapon 返回名为 name 的武器，未设置的属性取游戏默认值。
+func (r *Rules) Weapon(name string) Weapon {
+	weapon := Weapon{
+		Name:       name,
+		Damage:     int(r.number(name, "Damage", 0)),
+		ROF:        int(r.number(name, "ROF", 0)),
+		Range:      r.number(name, "Range", 0),
+		Burst:      int(r.number(name, "Burst", 1)),
+		Projectile: r.string(name, "Projectile"),
+		Warhead:    r.string(name, "Warhead"),
+	}
+	weapon.Verses = r.Verses(weapon.Warhead)
+	return weapon
+}
+
+// Verses 返回弹头 warhead 对 ArmorTypes 中各护甲的伤害倍率，缺少的项按 100% 计。
+func (r *Rules) Verses(warhead string) []float64 {
+	verses := make([]float64, len(ArmorTypes))
+	var items []string
+	if value := r.string(warhead, "Verses"); value != "" {
+		items = strings.Split(value, ",")
+	}
+	for i := range verses {
+		verses[i] = 1
+		if i < len(items) {
+			if n, ok := parseNumber(items[i]); ok {
+				verses[i] = n / 100
+			}
+		}
+	}
+	return verses
+}
+
+func (r *Rules) string(section, key string) string {
+	value, _ := r.Value(section, key)
+	return strings.TrimSpace(value)
+}
+
+// number 返回 section 中 key 的数值，未设置或无法解析时返回 def。
+func (r *Rules) number(section, key string, def float64) float64 {
+	if n, ok := parseNumber(r.string(section, key)); ok {
+		return n
+	}
+	return def
+}
+
+func numberedSlots(prefix string, n int) []string {
+	slots := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		slots = append(slots, prefix+strconv.Itoa(i))
+	}
+	return slots
+}
+
+// DPS 返回武器对 armor 护甲每秒的伤害，不计老兵加成。伤害或 ROF 不为正数时返回 0。
+func (w Weapon) DPS(armor string) float64 {
+	i := armorIndex(armor)
+	if w.Damage <= 0 || w.ROF <= 0 || i >= len(w.Verses) {
+		return 0
+	}
+	return float64(w.Damage) * w.Verses[i] * float64(max(w.Burst, 1)) * FramesPerSecond / float64(w.ROF)
+}
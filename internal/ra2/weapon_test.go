@@ -0,0 +1,59 @@
+package ra2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRules_Weapons(t *testing.T) {
+	rules := newTestRules(`[VehicleTypes]
+0=HTNK
+1=DRON
+2=TRUK
+[HTNK]
+Primary=120mm
+ElitePrimary=120mmE
+[DRON]
+WeaponCount=2
+Weapon1=Zap
+Weapon2=none
+[TRUK]
+Cost=500
+[120mm]
+Damage=90
+ROF=65
+Range=5.75
+Projectile=Cannon
+Warhead=AP
+[120mmE]
+Damage=90
+Burst=2
+Warhead=AP
+[Zap]
+Damage=5
+[AP]
+Verses=25%,25%,15%,75%,100%,100%,65%,45%,60%
+`)
+
+	verses := []float64{0.25, 0.25, 0.15, 0.75, 1, 1, 0.65, 0.45, 0.6, 1, 1}
+	allFull := []float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+	tests := []struct {
+		unit string
+		want []Weapon
+	}{
+		{unit: "HTNK", want: []Weapon{
+			{Slot: "Primary", Name: "120mm", Damage: 90, ROF: 65, Range: 5.75, Burst: 1, Projectile: "Cannon", Warhead: "AP", Verses: verses},
+			{Slot: "ElitePrimary", Name: "120mmE", Damage: 90, Burst: 2, Warhead: "AP", Verses: verses},
+		}},
+		{unit: "DRON", want: []Weapon{
+			{Slot: "Weapon1", Name: "Zap", Damage: 5, Burst: 1, Verses: allFull},
+		}},
+		{unit: "TRUK"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.unit, func(t *testing.T) {
+			assert.Equal(t, tt.want, rules.Weapons(rules.UnitByName(tt.unit)))
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package recent
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+
+	"github.com/pkg/errors"
+
+	"ra2-ini-editor/internal/fileutil"
+)
+
+// MaxFiles 是最近文件列表保留的最大数量
+const MaxFiles = 10
+
+// Files 是持久化在 filename 中的最近打开文件列表，最近使用的排在最前。
+type Files struct {
+	filename string
+	paths    []string
+}
+
+// Load 读取最近文件列表，文件不存在时返回空列表。
+func Load(filename string) (*Files, error) {
+	files := &Files{
+		filename: filename,
+	}
+	bts, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return files, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+	if err := json.Unmarshal(bts, &files.paths); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return files, nil
+}
+
+func (f *Files) List() []string {
+	return slices.Clone(f.paths)
+}
+
+// Add 将 path 移到列表最前并保存。
+func (f *Files) Add(path string) error {
+	f.paths = slices.DeleteFunc(f.paths, func(p string) bool {
+		return p == path
+	})
+	f.paths = slices.Insert(f.paths, 0, path)
+	if len(f.paths) > MaxFiles {
+		f.paths = f.paths[:MaxFiles]
+	}
+	return f.save()
+}
+
+// Remove 从列表中移除 path 并保存，例如文件已被删除时。
+func (f *Files) Remove(path string) error {
+	f.paths = slices.DeleteFunc(f.paths, func(p string) bool {
+		return p == path
+	})
+	return f.save()
+}
+
+func (f *Files) save() error {
+	bts, err := json.MarshalIndent(f.paths, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := fileutil.WriteFileAtomic(f.filename, bts, false); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package recent
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFiles(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "recent.json")
+
+	files, err := Load(filename)
+	assert.NoError(t, err)
+	assert.Empty(t, files.List())
+
+	assert.NoError(t, files.Add("a.ini"))
+	assert.NoError(t, files.Add("b.ini"))
+	assert.NoError(t, files.Add("a.ini"))
+	assert.Equal(t, []string{"a.ini", "b.ini"}, files.List())
+
+	files, err = Load(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.ini", "b.ini"}, files.List())
+
+	assert.NoError(t, files.Remove("a.ini"))
+	assert.Equal(t, []string{"b.ini"}, files.List())
+
+	for i := 0; i < MaxFiles+5; i++ {
+		assert.NoError(t, files.Add(fmt.Sprintf("%d.ini", i)))
+	}
+	assert.Len(t, files.List(), MaxFiles)
+	assert.Equal(t, fmt.Sprintf("%d.ini", MaxFiles+4), files.List()[0])
+}
@@ -0,0 +1,152 @@
+package recovery
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const manifestName = "manifest.json"
+
+// Manifest 描述一次自动保存的内容。
+type Manifest struct {
+	SavedAt time.Time `json:"saved_at"`
+	Target  string    `json:"target"` // 写入目标层的 ID
+	Layers  []Layer   `json:"layers"` // 按从底到顶的顺序排列
+	// Documents 是单独编辑的文件，如 AI 文件，按文件名（如 aimd.ini）索引
+	Documents map[string]Layer `json:"documents,omitempty"`
+}
+
+type Layer struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Enabled bool   `json:"enabled"`
+	Dirty   bool   `json:"dirty"`
+
+	Content []byte `json:"-"`
+}
+
+// Store 将自动保存的规则层写入 dir 目录。
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{
+		dir: dir,
+	}
+}
+
+// Save 覆盖之前的自动保存。新的内容先写入临时目录，完整写入后再替换之前的目录，
+// 写入失败或崩溃时之前的自动保存仍然可以读取。
+func (s *Store) Save(m *Manifest) error {
+	tmp := s.dir + ".tmp"
+	if err := os.RemoveAll(tmp); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := write(tmp, m); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+
+	// 目录不能直接覆盖，先移走之前的目录。两次重命名之间崩溃时 Load 读取移走的目录
+	old := s.dir + ".old"
+	if err := os.RemoveAll(old); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.Rename(s.dir, old); err != nil && !os.IsNotExist(err) {
+		return errors.WithStack(err)
+	}
+	if err := os.Rename(tmp, s.dir); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.RemoveAll(old); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func write(dir string, m *Manifest) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return errors.WithStack(err)
+	}
+	for _, layer := range m.Layers {
+		if err := os.WriteFile(layerFilename(dir, layer.ID), layer.Content, 0o644); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	for _, doc := range m.Documents {
+		if err := os.WriteFile(layerFilename(dir, doc.ID), doc.Content, 0o644); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	bts, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, manifestName), bts, 0o644); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Load 读取自动保存的内容，没有可恢复的内容时返回 nil。
+func (s *Store) Load() (*Manifest, error) {
+	m, err := load(s.dir)
+	if err != nil || m != nil {
+		return m, err
+	}
+	return load(s.dir + ".old")
+}
+
+func load(dir string) (*Manifest, error) {
+	bts, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(bts, &m); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for i := range m.Layers {
+		content, err := os.ReadFile(layerFilename(dir, m.Layers[i].ID))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		m.Layers[i].Content = content
+	}
+	for name, doc := range m.Documents {
+		content, err := os.ReadFile(layerFilename(dir, doc.ID))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		doc.Content = content
+		m.Documents[name] = doc
+	}
+	return &m, nil
+}
+
+// Clear 删除所有自动保存的内容。
+func (s *Store) Clear() error {
+	for _, dir := range []string{s.dir, s.dir + ".tmp", s.dir + ".old"} {
+		if err := os.RemoveAll(dir); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) layerFilename(id string) string {
+	return layerFilename(s.dir, id)
+}
+
+func layerFilename(dir, id string) string {
+	return filepath.Join(dir, id+".ini")
+}
@@ -0,0 +1,68 @@
+package recovery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "recovery"))
+
+	m, err := s.Load()
+	assert.NoError(t, err)
+	assert.Nil(t, m)
+
+	saved := &Manifest{
+		SavedAt: time.Date(2025, 5, 5, 12, 0, 0, 0, time.UTC),
+		Target:  "2",
+		Layers: []Layer{
+			{ID: "1", Name: "patch.ini", Path: "/mods/patch.ini", Enabled: true, Content: []byte("[E1]\nCost=120\n")},
+			{ID: "2", Name: "user", Enabled: true, Dirty: true, Content: []byte("[E1]\nCost=150\n")},
+		},
+		Documents: map[string]Layer{
+			"aimd.ini": {ID: "3", Name: "aimd.ini", Enabled: true, Dirty: true, Content: []byte("[TaskForces]\n")},
+		},
+	}
+	assert.NoError(t, s.Save(saved))
+
+	m, err = s.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, saved, m)
+
+	assert.NoError(t, s.Save(&Manifest{Target: "4", Layers: []Layer{{ID: "4", Content: []byte{}}}}))
+	m, err = s.Load()
+	assert.NoError(t, err)
+	assert.Len(t, m.Layers, 1)
+	assert.NoFileExists(t, s.layerFilename("1"))
+
+	assert.NoError(t, s.Clear())
+	m, err = s.Load()
+	assert.NoError(t, err)
+	assert.Nil(t, m)
+}
+
+func TestStore_SaveFailed(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "recovery")
+	s := NewStore(dir)
+	saved := &Manifest{Target: "1", Layers: []Layer{{ID: "1", Name: "user", Enabled: true, Content: []byte("[E1]\nCost=150\n")}}}
+	assert.NoError(t, s.Save(saved))
+
+	// 写入失败时保留之前的自动保存
+	assert.Error(t, s.Save(&Manifest{Target: "2", Layers: []Layer{{ID: "missing/2", Content: []byte{}}}}))
+	m, err := s.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, saved, m)
+	assert.NoDirExists(t, dir+".tmp")
+
+	// 替换目录的过程中崩溃时读取移走的目录
+	assert.NoError(t, os.Rename(dir, dir+".old"))
+	m, err = s.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, saved, m)
+	assert.NoError(t, s.Save(saved))
+	assert.NoDirExists(t, dir+".old")
+}
@@ -0,0 +1,271 @@
+// Package sheet 在 CSV、XLSX 表格与规则之间导入导出单位数值，供在电子表格中平衡数值。
+package sheet
+
+import (
+	"encoding/csv"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/xuri/excelize/v2"
+
+	"ra2-ini-editor/internal/ra2"
+)
+
+// Format 是表格文件的格式。
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// FormatOf 按扩展名判断 filename 的格式。
+func FormatOf(filename string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, true
+	case ".xlsx":
+		return FormatXLSX, true
+	default:
+		return "", false
+	}
+}
+
+// sectionHeader 是表头第一列的名称，读取时不检查
+const sectionHeader = "Section"
+
+// xlsxSheet 是导出时工作表的名称，读取时使用第一个工作表
+const xlsxSheet = "Sheet1"
+
+// Table 以 section 为行、key 为列，第一列为 section 名。
+type Table struct {
+	Keys []string
+	Rows []Row
+}
+
+// Row 是一个 section 的值，Values 与 Table.Keys 一一对应，空值表示未设置。
+type Row struct {
+	Section string
+	Values  []string
+}
+
+// Export 返回 sections 在 keys 上的值，未设置的 key 为空。
+func Export(rules *ra2.Rules, sections, keys []string) *Table {
+	t := &Table{Keys: keys}
+	for _, section := range sections {
+		row := Row{
+			Section: section,
+			Values:  make([]string, len(keys)),
+		}
+		for i, key := range keys {
+			row.Values[i], _ = rules.Value(section, key)
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+// EconomyTable 返回经济计算结果的表格，列为 ra2.EconomyColumns，只用于导出。
+func EconomyTable(rows []ra2.EconomyRow) *Table {
+	t := &Table{Keys: ra2.EconomyColumns}
+	for _, row := range rows {
+		t.Rows = append(t.Rows, Row{Section: row.Name, Values: row.Values()})
+	}
+	return t
+}
+
+// Plan 返回将 t 导入 rules 时的修改，rules 为合并后的规则。空单元格不做修改。
+// 不存在的 section 与不符合 schema 类型的值作为问题返回，此时不应导入。
+func (t *Table) Plan(rules *ra2.Rules, schema *ra2.Schema) ([]ra2.BulkChange, []ra2.Problem) {
+	var (
+		changes  []ra2.BulkChange
+		problems []ra2.Problem
+	)
+	for _, row := range t.Rows {
+		if !rules.HasSection(row.Section) {
+			problems = append(problems, ra2.Problem{Section: row.Section, Message: "section not found"})
+			continue
+		}
+		unitType := ra2.UnitTypeUnknown
+		if unit := rules.UnitByName(row.Section); unit != nil {
+			unitType = unit.Type
+		}
+		for i, key := range t.Keys {
+			value := strings.TrimSpace(row.Values[i])
+			if value == "" {
+				continue
+			}
+			if err := schema.ValidateValue(unitType, row.Section, key, value); err != nil {
+				problems = append(problems, ra2.Problem{Section: row.Section, Key: key, Message: err.Error()})
+				continue
+			}
+			old, _ := rules.Value(row.Section, key)
+			if sameValue(old, value) {
+				continue
+			}
+			changes = append(changes, ra2.BulkChange{
+				Section: row.Section,
+				Type:    unitType,
+				Key:     key,
+				Old:     old,
+				New:     value,
+			})
+		}
+	}
+	return changes, problems
+}
+
+// sameValue 返回表格中的 value 与 INI 中的 old 是否相同。XLSX 中的数值按数字保存，
+// 读回时 1.0 会变为 1，因此数值按大小比较。
+func sameValue(old, value string) bool {
+	if strings.TrimSpace(old) == value {
+		return true
+	}
+	a, errA := strconv.ParseFloat(strings.TrimSpace(old), 64)
+	b, errB := strconv.ParseFloat(value, 64)
+	return errA == nil && errB == nil && a == b
+}
+
+// Write 以 format 格式写出 t。
+func Write(w io.Writer, format Format, t *Table) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, t)
+	case FormatXLSX:
+		return writeXLSX(w, t)
+	default:
+		return errors.Errorf("unknown sheet format %q", format)
+	}
+}
+
+// Read 读取 format 格式的表格。第一行为表头，其后每行的第一列为 section 名，
+// 表头为空的列与 section 名为空的行被忽略。
+func Read(r io.Reader, format Format) (*Table, error) {
+	var (
+		records [][]string
+		err     error
+	)
+	switch format {
+	case FormatCSV:
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+		records, err = cr.ReadAll()
+	case FormatXLSX:
+		records, err = readXLSX(r)
+	default:
+		return nil, errors.Errorf("unknown sheet format %q", format)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return parseRecords(records)
+}
+
+func header(t *Table) []string {
+	return append([]string{sectionHeader}, t.Keys...)
+}
+
+func writeCSV(w io.Writer, t *Table) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header(t)); err != nil {
+		return errors.WithStack(err)
+	}
+	for _, row := range t.Rows {
+		if err := cw.Write(append([]string{row.Section}, row.Values...)); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	cw.Flush()
+	return errors.WithStack(cw.Error())
+}
+
+func writeXLSX(w io.Writer, t *Table) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	head := lo.ToAnySlice(header(t))
+	if err := f.SetSheetRow(xlsxSheet, "A1", &head); err != nil {
+		return errors.WithStack(err)
+	}
+	for i, row := range t.Rows {
+		cells := []any{row.Section}
+		for _, v := range row.Values {
+			// 数值写为数字，便于在表格中计算
+			if n, err := strconv.ParseFloat(v, 64); err == nil {
+				cells = append(cells, n)
+			} else {
+				cells = append(cells, v)
+			}
+		}
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if err := f.SetSheetRow(xlsxSheet, cell, &cells); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if err := f.SetPanes(xlsxSheet, &excelize.Panes{
+		Freeze:      true,
+		XSplit:      1,
+		YSplit:      1,
+		TopLeftCell: "B2",
+		ActivePane:  "bottomRight",
+	}); err != nil {
+		return errors.WithStack(err)
+	}
+	_, err := f.WriteTo(w)
+	return errors.WithStack(err)
+}
+
+func readXLSX(r io.Reader) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer f.Close()
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New("workbook has no sheets")
+	}
+	rows, err := f.GetRows(sheets[0])
+	return rows, errors.WithStack(err)
+}
+
+func parseRecords(records [][]string) (*Table, error) {
+	if len(records) == 0 {
+		return nil, errors.New("sheet is empty")
+	}
+	head := records[0]
+	t := &Table{}
+	var columns []int
+	for i := 1; i < len(head); i++ {
+		if key := strings.TrimSpace(head[i]); key != "" {
+			t.Keys = append(t.Keys, key)
+			columns = append(columns, i)
+		}
+	}
+	if len(t.Keys) == 0 {
+		return nil, errors.New("sheet has no key columns")
+	}
+	for _, record := range records[1:] {
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		row := Row{
+			Section: strings.TrimSpace(record[0]),
+			Values:  make([]string, len(columns)),
+		}
+		for i, col := range columns {
+			if col < len(record) {
+				row.Values[i] = record[col]
+			}
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t, nil
+}
@@ -0,0 +1,131 @@
+package sheet
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ra2-ini-editor/internal/ra2"
+)
+
+func loadTestRules(t *testing.T, content string) *ra2.Rules {
+	rules, err := ra2.NewRules(io.NopCloser(strings.NewReader(content)))
+	require.NoError(t, err)
+	return rules
+}
+
+func loadTestSchema(t *testing.T) *ra2.Schema {
+	f, err := os.Open("../../data/schema/schema.json")
+	require.NoError(t, err)
+	defer f.Close()
+	schema, err := ra2.LoadSchema(f)
+	require.NoError(t, err)
+	return schema
+}
+
+const testRules = `[VehicleTypes]
+0=HTNK
+1=MTNK
+[HTNK]
+Cost=900
+Speed=5
+Armor=heavy
+[MTNK]
+Cost=700
+Speed=7
+Owner=Americans,British
+`
+
+func TestRoundTrip(t *testing.T) {
+	rules := loadTestRules(t, testRules)
+	table := Export(rules, []string{"HTNK", "MTNK"}, []string{"Cost", "Armor", "Owner"})
+	assert.Equal(t, []Row{
+		{Section: "HTNK", Values: []string{"900", "heavy", ""}},
+		{Section: "MTNK", Values: []string{"700", "", "Americans,British"}},
+	}, table.Rows)
+
+	for _, format := range []Format{FormatCSV, FormatXLSX} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Write(&buf, format, table))
+			got, err := Read(&buf, format)
+			require.NoError(t, err)
+			assert.Equal(t, table, got)
+		})
+	}
+}
+
+// TestRoundTrip_NoChanges 未修改的导出表格再导入时没有修改。
+func TestRoundTrip_NoChanges(t *testing.T) {
+	rules := loadTestRules(t, testRules+"[Veteran]\nSpeed=1.0\nCost=0.50\n")
+	table := Export(rules, []string{"HTNK", "MTNK", "Veteran"}, []string{"Cost", "Speed", "Armor", "Owner"})
+	for _, format := range []Format{FormatCSV, FormatXLSX} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Write(&buf, format, table))
+			got, err := Read(&buf, format)
+			require.NoError(t, err)
+			changes, problems := got.Plan(rules, loadTestSchema(t))
+			assert.Empty(t, changes)
+			assert.Empty(t, problems)
+		})
+	}
+}
+
+func TestRead_CSV(t *testing.T) {
+	content := "Unit,Cost,,Speed\nHTNK,1000,ignored\n,1,2,3\nMTNK ,,x,8,extra\n"
+	got, err := Read(strings.NewReader(content), FormatCSV)
+	require.NoError(t, err)
+	assert.Equal(t, &Table{
+		Keys: []string{"Cost", "Speed"},
+		Rows: []Row{
+			{Section: "HTNK", Values: []string{"1000", ""}},
+			{Section: "MTNK", Values: []string{"", "8"}},
+		},
+	}, got)
+
+	_, err = Read(strings.NewReader("Section\nHTNK\n"), FormatCSV)
+	assert.EqualError(t, err, "sheet has no key columns")
+}
+
+func TestTable_Plan(t *testing.T) {
+	rules := loadTestRules(t, testRules)
+	table := &Table{
+		Keys: []string{"Cost", "Speed", "Crusher"},
+		Rows: []Row{
+			{Section: "HTNK", Values: []string{"990", "5", ""}},
+			{Section: "MTNK", Values: []string{"7.5", "", "sometimes"}},
+			{Section: "NOPE", Values: []string{"1", "", ""}},
+		},
+	}
+
+	changes, problems := table.Plan(rules, loadTestSchema(t))
+	assert.Equal(t, []ra2.BulkChange{
+		{Section: "HTNK", Type: ra2.UnitTypeVehicle, Key: "Cost", Old: "900", New: "990"},
+	}, changes)
+	assert.Equal(t, []ra2.Problem{
+		{Section: "MTNK", Key: "Cost", Message: `"7.5" is not a valid int`},
+		{Section: "MTNK", Key: "Crusher", Message: `"sometimes" is not a valid boolean`},
+		{Section: "NOPE", Message: "section not found"},
+	}, problems)
+}
+
+func TestFormatOf(t *testing.T) {
+	format, ok := FormatOf("units.XLSX")
+	assert.True(t, ok)
+	assert.Equal(t, FormatXLSX, format)
+	_, ok = FormatOf("units.xls")
+	assert.False(t, ok)
+}
+
+func TestEconomyTable(t *testing.T) {
+	rows := []ra2.EconomyRow{{Name: "HTNK", Type: ra2.UnitTypeVehicle, Cost: 900, Strength: 400, BuildTime: 56.7, CostPerHP: 2.25}}
+	table := EconomyTable(rows)
+	assert.Equal(t, ra2.EconomyColumns, table.Keys)
+	assert.Equal(t, []Row{{Section: "HTNK", Values: []string{"vehicle", "900", "400", "56.7", "2.25", "0", "0", "0"}}}, table.Rows)
+}
@@ -0,0 +1,110 @@
+package workspace
+
+import (
+	"io"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+
+	"ra2-ini-editor/internal/mix"
+	"ra2-ini-editor/internal/ra2"
+)
+
+// DefaultPackageName 是未指定输出文件时打包使用的文件名，游戏会优先加载编号大的 expandmd*.mix
+const DefaultPackageName = "expandmd99.mix"
+
+// Package 将合并后的规则、编辑过的 AI 等文件及项目中配置的打包文件写成 MIX。
+// 游戏会用 expandmd*.mix 中的文件整体替换同名文件，所以规则与 art 写入的是合并后的完整内容。
+// 地图层只对该地图生效，不打包到全局规则中。
+func (ws *Workspace) Package(out io.Writer) error {
+	w := mix.NewWriter()
+
+	layers := lo.Filter(ws.Layers.Layers(), func(l *ra2.Layer, _ int) bool { return l.Map == nil })
+	rules, err := ra2.NewLayerStack(layers...).Merged()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := addRules(w, ra2.GameFileRules, rules); err != nil {
+		return err
+	}
+	for _, doc := range []struct {
+		name  string
+		layer *ra2.Layer
+	}{
+		{ra2.GameFileAI, ws.AI},
+		{ra2.GameFileSound, ws.Sound},
+		{ra2.GameFileEVA, ws.EVA},
+	} {
+		if !edited(doc.layer) {
+			continue
+		}
+		if err := addRules(w, doc.name, doc.layer.Rules); err != nil {
+			return err
+		}
+	}
+
+	pkg := ws.Project.Package
+	if pkg.Art != "" {
+		art, err := LoadArt(ws.GameFiles)
+		if err != nil {
+			return err
+		}
+		override, err := loadDataFile(ws.Project.Resolve(pkg.Art), "", ra2.NewArt)
+		if err != nil {
+			return errors.Wrap(err, "load art override")
+		}
+		merged, err := art.Merge(override)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if err := addRules(w, ra2.GameFileArt, merged); err != nil {
+			return err
+		}
+	}
+	if pkg.CSF != "" {
+		if err := addFile(w, ra2.GameFileCSF, ws.Project.Resolve(pkg.CSF)); err != nil {
+			return err
+		}
+	}
+	for _, file := range pkg.Files {
+		if err := addFile(w, filepath.Base(file), ws.Project.Resolve(file)); err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.WriteTo(out); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// edited 返回单独编辑的文件是否需要打包：有未保存的修改，或者来自 MIX 之外的文件。
+// 从游戏 MIX 中读取且未修改的文件与游戏自带的相同，不需要打包。
+func edited(doc *ra2.Layer) bool {
+	if doc == nil || len(doc.Rules.Sections()) == 0 {
+		return false
+	}
+	return doc.Dirty || doc.Path != ""
+}
+
+func addRules(w *mix.Writer, name string, rules *ra2.Rules) error {
+	content, err := rules.Content()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return w.Add(name, content)
+}
+
+func addFile(w *mix.Writer, name, filename string) error {
+	f, err := mix.OpenPath(filename)
+	if err != nil {
+		return errors.Wrapf(err, "open %s", filename)
+	}
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return w.Add(name, content)
+}
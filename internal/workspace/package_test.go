@@ -0,0 +1,77 @@
+package workspace
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"ra2-ini-editor/internal/mix"
+	"ra2-ini-editor/internal/project"
+	"ra2-ini-editor/internal/ra2"
+)
+
+func TestWorkspace_Package(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"rules.ini": "[HTNK]\nCost=1000\n",
+		"test.map":  "[Basic]\nName=Test\n[HTNK]\nCost=1\n",
+		"art.ini":   "[HTNK]\nVoxel=no\n",
+		"mod.csf":   " FSC",
+		"logo.pcx":  "PCX",
+	}
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	p := project.Default()
+	p.Layers = []project.Layer{
+		{Name: "mod", Path: filepath.Join(dir, "rules.ini"), Enabled: true, Target: true},
+		{Name: "map", Path: filepath.Join(dir, "test.map"), Enabled: true},
+	}
+	p.Package = project.Package{
+		Art:   filepath.Join(dir, "art.ini"),
+		CSF:   filepath.Join(dir, "mod.csf"),
+		Files: []string{filepath.Join(dir, "logo.pcx")},
+	}
+	ws, err := Load(p)
+	assert.NoError(t, err)
+	// 编辑过的 AI 文件一同打包
+	assert.NoError(t, ra2.NewAI(ws.AI.Rules).SetTaskForce(&ra2.TaskForce{ID: "01000000-G", Name: "Rhinos"}))
+	ws.AI.Dirty = true
+
+	var buf bytes.Buffer
+	assert.NoError(t, ws.Package(&buf))
+
+	a, err := mix.New(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"aimd.ini", "artmd.ini", "logo.pcx", "ra2md.csf", "rulesmd.ini"}, a.Names())
+
+	f, err := a.File(ra2.GameFileRules)
+	assert.NoError(t, err)
+	rules, err := ra2.NewRules(io.NopCloser(f))
+	assert.NoError(t, err)
+	htnk := rules.GetUnit(ra2.UnitTypeVehicle, 4)
+	if assert.NotNil(t, htnk) {
+		assert.Equal(t, "HTNK", htnk.Name)
+		assert.Equal(t, "1000", htnk.Get("Cost"), "map layers should not be packaged")
+		assert.NotEmpty(t, htnk.Get("Strength"), "packaged rules should contain the base rules")
+	}
+
+	f, err = a.File(ra2.GameFileArt)
+	assert.NoError(t, err)
+	art, err := io.ReadAll(f)
+	assert.NoError(t, err)
+	assert.Contains(t, string(art), "Voxel")
+
+	ai, err := a.ReadFile(ra2.GameFileAI)
+	assert.NoError(t, err)
+	assert.Contains(t, string(ai), "Rhinos")
+
+	csf, err := a.ReadFile(ra2.GameFileCSF)
+	assert.NoError(t, err)
+	assert.Equal(t, " FSC", string(csf))
+}
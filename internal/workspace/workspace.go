@@ -0,0 +1,303 @@
+package workspace
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"ra2-ini-editor/data"
+	"ra2-ini-editor/internal/mix"
+	"ra2-ini-editor/internal/project"
+	"ra2-ini-editor/internal/ra2"
+)
+
+// Workspace 是按项目设置加载好的 schema、翻译与规则栈，供界面和命令行共用。
+type Workspace struct {
+	Project     *project.Project
+	GameFiles   *ra2.GameFiles
+	Schema      *ra2.Schema
+	Translation *ra2.Translation
+	Layers      *ra2.LayerStack
+	// 以下文件不参与规则栈的合并
+	AI    *ra2.Layer
+	Sound *ra2.Layer
+	EVA   *ra2.Layer
+}
+
+// Load 按项目设置加载所有数据，规则栈的最底层为只读的基础规则。
+func Load(p *project.Project) (*Workspace, error) {
+	schema, err := LoadSchema(p)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := ResolveGameFiles(p)
+	if err != nil {
+		return nil, err
+	}
+	translation, origin, err := LoadBaseData(files, p.Language)
+	if err != nil {
+		return nil, err
+	}
+	base := ra2.NewLayer("rulesmd.ini", origin)
+	base.ReadOnly = true
+	layers := ra2.NewLayerStack(base)
+	for _, l := range p.Layers {
+		layer := ra2.NewLayer(l.Name, ra2.NewEmptyRules())
+		if path := p.Resolve(l.Path); path != "" {
+			layer, err = LoadLayer(path)
+			if err != nil {
+				return nil, errors.Wrapf(err, "load layer %s", l.Name)
+			}
+			layer.Name = l.Name
+		}
+		layer.Enabled = l.Enabled
+		if err := layers.Add(layer); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if l.Target {
+			if err := layers.SetTarget(layer.ID); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+	}
+
+	ai, err := LoadAI(files)
+	if err != nil {
+		return nil, err
+	}
+	sound, err := LoadSound(files)
+	if err != nil {
+		return nil, err
+	}
+	eva, err := LoadEVA(files)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Workspace{
+		Project:     p,
+		GameFiles:   files,
+		Schema:      schema,
+		Translation: translation,
+		Layers:      layers,
+		AI:          ai,
+		Sound:       sound,
+		EVA:         eva,
+	}, nil
+}
+
+// ResolveGameFiles 按项目设置确定基础数据文件：优先使用 Base 中指定的文件，
+// 其次在 GameDir 及其 MIX 文件中查找，都没有的文件为空，加载时使用内置数据。
+func ResolveGameFiles(p *project.Project) (*ra2.GameFiles, error) {
+	files := &ra2.GameFiles{}
+	if p.GameDir != "" {
+		dir := p.Resolve(p.GameDir)
+		found, err := ra2.FindGameFiles(dir)
+		if err != nil {
+			return nil, errors.Wrap(err, "find game files")
+		}
+		files = found
+		// 没有解包的文件从 MIX 中读取
+		for name, path := range map[string]*string{
+			ra2.GameFileRules: &files.Rules,
+			ra2.GameFileArt:   &files.Art,
+			ra2.GameFileAI:    &files.AI,
+			ra2.GameFileSound: &files.Sound,
+			ra2.GameFileEVA:   &files.EVA,
+			ra2.GameFileCSF:   &files.CSF,
+		} {
+			if *path != "" {
+				continue
+			}
+			if *path, err = mix.Find(dir, name); err != nil {
+				return nil, errors.Wrapf(err, "find %s in mix", name)
+			}
+		}
+	}
+	if p.Base.Rules != "" {
+		files.Rules = p.Resolve(p.Base.Rules)
+	}
+	if p.Base.Art != "" {
+		files.Art = p.Resolve(p.Base.Art)
+	}
+	if p.Base.AI != "" {
+		files.AI = p.Resolve(p.Base.AI)
+	}
+	if p.Base.Sound != "" {
+		files.Sound = p.Resolve(p.Base.Sound)
+	}
+	if p.Base.EVA != "" {
+		files.EVA = p.Resolve(p.Base.EVA)
+	}
+	if p.Base.CSF != "" {
+		files.CSF = p.Resolve(p.Base.CSF)
+	}
+	return files, nil
+}
+
+// LoadBaseData 加载翻译与基础规则。
+func LoadBaseData(files *ra2.GameFiles, language string) (*ra2.Translation, *ra2.Rules, error) {
+	var (
+		translation *ra2.Translation
+		err         error
+	)
+	if files.CSF != "" {
+		translation, err = loadDataFile(files.CSF, "", ra2.LoadCSF)
+	} else {
+		translation, err = loadDataFile("", "ra2md.ini", func(r io.ReadCloser) (*ra2.Translation, error) {
+			return ra2.LoadTranslation(r, language)
+		})
+	}
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "load translation")
+	}
+
+	origin, err := loadDataFile(files.Rules, "rulesmd.ini", ra2.NewRules)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "load base rules")
+	}
+	return translation, origin, nil
+}
+
+// LoadArt 加载基础 art 文件。
+func LoadArt(files *ra2.GameFiles) (*ra2.Rules, error) {
+	art, err := loadDataFile(files.Art, "artmd.ini", ra2.NewArt)
+	if err != nil {
+		return nil, errors.Wrap(err, "load base art")
+	}
+	return art, nil
+}
+
+// LoadAI 加载 AI 文件，没有时返回空层。
+func LoadAI(files *ra2.GameFiles) (*ra2.Layer, error) {
+	return loadDocument(ra2.GameFileAI, files.AI)
+}
+
+// LoadSound 加载音效文件，没有时返回空层。
+func LoadSound(files *ra2.GameFiles) (*ra2.Layer, error) {
+	return loadDocument(ra2.GameFileSound, files.Sound)
+}
+
+// LoadEVA 加载 EVA 语音文件，没有时返回空层。
+func LoadEVA(files *ra2.GameFiles) (*ra2.Layer, error) {
+	return loadDocument(ra2.GameFileEVA, files.EVA)
+}
+
+// loadDocument 将 filename 加载为单独编辑的层，filename 为空时返回空层。
+// 文件位于 MIX 中时不关联路径，保存时需另选文件。
+func loadDocument(name, filename string) (*ra2.Layer, error) {
+	layer := ra2.NewLayer(name, ra2.NewEmptyRules())
+	if filename == "" {
+		return layer, nil
+	}
+	rules, err := LoadRules(filename)
+	if err != nil {
+		return nil, errors.Wrapf(err, "load %s", name)
+	}
+	layer.Rules = rules
+	if fi, err := os.Stat(filename); err == nil && fi.Mode().IsRegular() {
+		layer.Path = filename
+	}
+	return layer, nil
+}
+
+// LoadSchema 加载 schema 及项目中的 schema 扩展。
+func LoadSchema(p *project.Project) (*ra2.Schema, error) {
+	schema, err := loadDataFile(p.Resolve(p.Schema), "schema/schema.zh.json", func(r io.ReadCloser) (*ra2.Schema, error) {
+		return ra2.LoadSchema(r)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "load schema")
+	}
+	for _, ext := range p.SchemaExtensions {
+		extSchema, err := loadDataFile(p.Resolve(ext), "", func(r io.ReadCloser) (*ra2.Schema, error) {
+			return ra2.LoadSchema(r)
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "load schema extension %s", ext)
+		}
+		schema.Extend(extSchema)
+	}
+	if p.Engine != "" {
+		engineSchema, err := LoadEngineSchema(p.Engine)
+		if err != nil {
+			return nil, err
+		}
+		schema.Extend(engineSchema)
+	}
+	return schema, nil
+}
+
+// LoadEngineSchema 加载内置的引擎扩展 flag，目前只支持 Ares。
+func LoadEngineSchema(engine string) (*ra2.Schema, error) {
+	if engine != project.EngineAres {
+		return nil, errors.Errorf("unknown engine %q", engine)
+	}
+	schema, err := loadDataFile("", "schema/ares.json", func(r io.ReadCloser) (*ra2.Schema, error) {
+		return ra2.LoadSchema(r)
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "load %s schema", engine)
+	}
+	return schema, nil
+}
+
+// LoadLayer 将 filename 加载为覆盖层。地图文件只将其中的规则覆盖作为 Rules，
+// 其他内容在保存时原样写回。
+func LoadLayer(filename string) (*ra2.Layer, error) {
+	return loadDataFile(filename, "", func(r io.ReadCloser) (*ra2.Layer, error) {
+		var (
+			m     *ra2.MapFile
+			rules *ra2.Rules
+			err   error
+		)
+		if ra2.IsMapFile(filename) {
+			m, rules, err = ra2.LoadMap(r)
+		} else {
+			rules, err = ra2.NewRules(r)
+		}
+		if err != nil {
+			return nil, err
+		}
+		layer := ra2.NewLayer(filepath.Base(filename), rules)
+		layer.Path = filename
+		layer.Map = m
+		return layer, nil
+	})
+}
+
+// LoadRules 加载磁盘上或 MIX 文件中的 INI 文件。
+func LoadRules(filename string) (*ra2.Rules, error) {
+	return loadDataFile(filename, "", ra2.NewRules)
+}
+
+// loadDataFile 使用 load 读取 filename，filename 为空时读取内置的 embedded 文件。
+// filename 可以是 MIX 文件中的文件，见 mix.OpenPath。解析错误会记录出错的文件名。
+func loadDataFile[T any](filename, embedded string, load func(io.ReadCloser) (T, error)) (T, error) {
+	var (
+		f   io.ReadCloser
+		err error
+	)
+	if filename != "" {
+		f, err = mix.OpenPath(filename)
+	} else {
+		f, err = data.FS.Open(embedded)
+	}
+	if err != nil {
+		var zero T
+		return zero, errors.WithStack(err)
+	}
+	defer f.Close()
+	v, err := load(f)
+	var parseErr *ra2.ParseError
+	if errors.As(err, &parseErr) && parseErr.File == "" {
+		parseErr.File = filename
+		if filename == "" {
+			parseErr.File = embedded
+		}
+	}
+	return v, err
+}
@@ -25,7 +25,11 @@ var assets embed.FS
 
 func main() {
 	// Create an instance of the app structure
-	app := NewApp()
+	app, err := NewApp()
+	if err != nil {
+		println("Error:", err.Error())
+		os.Exit(1)
+	}
 
 	logFile, err := getLogFile()
 	if err != nil {
@@ -48,6 +52,7 @@ func main() {
 		Logger:           &logger.Logger{},
 		BackgroundColour: options.NewRGBA(255, 255, 255, 0),
 		OnStartup:        app.startup,
+		OnShutdown:       app.shutdown,
 		Bind: []interface{}{
 			app,
 		},
@@ -59,14 +64,10 @@ func main() {
 
 func getLogFilename() (string, error) {
 	name := fmt.Sprintf("%d.log", time.Now().Unix())
-	dir, err := os.UserConfigDir()
+	dir, err := getConfigDir()
 	if err != nil {
 		return "", err
 	}
-	dir = filepath.Join(dir, "ra2-ini-editor")
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return "", err
-	}
 	return filepath.Join(dir, name), nil
 }
 